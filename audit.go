@@ -0,0 +1,220 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// AuditLogTable is the audit table which can be shared by models, instead of
+// a <table>_history table per model.
+const AuditLogTable = "audit_log"
+
+// The operations recorded in audit tables.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDestroy = "destroy"
+)
+
+// Auditable models have their Create, CreateMany, Update, UpdateColumns,
+// Upsert, UpsertIgnore, Destroy, HardDestroy and Restore, as well as the rows
+// changed by Query.UpdateQuery and Query.Delete, recorded in an audit table,
+// in the same transaction as the change, which is started if the connection
+// isn't in one. Upserts of auditable models require conflict columns, which
+// find the row they update. AuditTable returns the name of the audit table, usually the
+// <table>_history table of the model, or AuditLogTable. Such tables are
+// created with `soda generate audit`.
+//
+// Every entry holds the table and ID of the model, the operation, the values
+// of the columns before and after the change as JSON, the actor set with
+// WithAuditActor, and the time of the change.
+//
+//	func (User) AuditTable() string {
+//		return "users_history"
+//	}
+type Auditable interface {
+	AuditTable() string
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor returns a copy of the context carrying the actor recorded in
+// the audit entries of the statements run with it, such as the name or ID of
+// the current user.
+//
+//	c.WithContext(pop.WithAuditActor(ctx, user.Email)).Update(&widget)
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActor returns the actor carried by the context, or an empty string.
+func AuditActor(ctx context.Context) string {
+	actor, _ := ctx.Value(auditActorKey{}).(string)
+	return actor
+}
+
+var auditableType = reflect.TypeOf((*Auditable)(nil)).Elem()
+
+// isAuditable returns true if the model, or the elements of a slice of
+// models, are auditable.
+func isAuditable(model interface{}) bool {
+	t := reflect.TypeOf(model)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	return t != nil && (t.Implements(auditableType) || reflect.PtrTo(t).Implements(auditableType))
+}
+
+// needsAuditTransaction returns true if the model is auditable and the
+// connection isn't in a transaction, which must then be started so the audit
+// entries are written along with the change.
+func (c *Connection) needsAuditTransaction(model interface{}) bool {
+	return c.TX == nil && isAuditable(model)
+}
+
+// auditState returns the values of the columns of an auditable model as they
+// are in the database, or nil if the model isn't auditable.
+func (c *Connection) auditState(m *Model) (map[string]interface{}, error) {
+	if _, ok := m.Value.(Auditable); !ok {
+		return nil, nil
+	}
+	return c.currentValues(m, m.WhereID(), m.ID())
+}
+
+// upsertAuditState returns the values of the columns of the row an upsert of
+// an auditable model conflicts with, or nil if there is none or the model
+// isn't auditable.
+func (c *Connection) upsertAuditState(m *Model, conflictColumns []string) (map[string]interface{}, error) {
+	if _, ok := m.Value.(Auditable); !ok {
+		return nil, nil
+	}
+	if len(conflictColumns) == 0 {
+		return nil, fmt.Errorf("upserts of the auditable %s require conflict columns", m.TableName())
+	}
+
+	values := m.columnValues(m.Columns())
+	where := make([]string, 0, len(conflictColumns))
+	args := make([]interface{}, 0, len(conflictColumns))
+	for _, col := range conflictColumns {
+		where = append(where, fmt.Sprintf("%s.%s = ?", m.Alias(), col))
+		args = append(args, values[col])
+	}
+	before, err := c.currentValues(m, strings.Join(where, " AND "), args...)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return before, err
+}
+
+// currentValues returns the values of the columns of the row of the table of
+// the model matched by the where clause, soft deleted or not.
+func (c *Connection) currentValues(m *Model, where string, args ...interface{}) (map[string]interface{}, error) {
+	current := NewModel(reflect.New(reflect.Indirect(reflect.ValueOf(m.Value)).Type()).Interface(), c.Context())
+	current.As = m.As
+	q := Q(c).Unscoped().Where(where, args...)
+	if err := c.Dialect.SelectOne(c, current, *q); err != nil {
+		return nil, c.notFoundError(err)
+	}
+	return current.columnValues(current.Columns()), nil
+}
+
+// auditRows returns the rows matched by the query, along with the values of
+// their columns, if the model is auditable.
+func (q *Query) auditRows(m *Model) ([]*Model, []map[string]interface{}, error) {
+	if !isAuditable(m.Value) {
+		return nil, nil, nil
+	}
+	t := reflect.TypeOf(m.Value)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	rows := reflect.New(reflect.SliceOf(t))
+	sm := NewModel(rows.Interface(), m.ctx)
+	sm.As = m.As
+
+	sel := Q(q.Connection)
+	q.Clone(sel)
+	sel.Operation = Select
+	if err := q.Connection.Dialect.SelectMany(q.Connection, sm, *sel); err != nil {
+		return nil, nil, q.Connection.translateError(err)
+	}
+
+	rv := rows.Elem()
+	models := make([]*Model, 0, rv.Len())
+	before := make([]map[string]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		row := &Model{Value: rv.Index(i).Addr().Interface(), ctx: m.ctx, As: m.As}
+		models = append(models, row)
+		before = append(before, row.columnValues(row.Columns()))
+	}
+	return models, before, nil
+}
+
+// audit records the change of an auditable model, whose columns had the given
+// values before. The values after the change are read from the model, unless
+// it was deleted.
+func (c *Connection) audit(m *Model, op string, before map[string]interface{}, deleted bool) error {
+	var after map[string]interface{}
+	if !deleted {
+		after = m.columnValues(m.Columns())
+	}
+	return c.writeAudit(m, op, before, after)
+}
+
+// writeAudit records the change of the columns of an auditable model from
+// the values before to the values after.
+func (c *Connection) writeAudit(m *Model, op string, before, after map[string]interface{}) error {
+	a, ok := m.Value.(Auditable)
+	if !ok {
+		return nil
+	}
+
+	oldValues, err := auditJSON(before)
+	if err != nil {
+		return err
+	}
+	newValues, err := auditJSON(after)
+	if err != nil {
+		return err
+	}
+	// NULL is bound typed, as the YDB driver rejects untyped nil values.
+	actor := sql.NullString{}
+	if s := AuditActor(c.Context()); s != "" {
+		actor = sql.NullString{String: s, Valid: true}
+	}
+
+	now := nowFunc().Truncate(time.Microsecond)
+	stmt := c.Dialect.TranslateSQL(fmt.Sprintf("INSERT INTO %s (table_name, record_id, operation, old_values, new_values, actor, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", c.Dialect.Quote(a.AuditTable())))
+	_, err = GenericExec(c, stmt, m.TableName(), fmt.Sprint(m.ID()), op, oldValues, newValues, actor, now, now)
+	if err != nil {
+		return fmt.Errorf("couldn't write audit entry to %s: %w", a.AuditTable(), err)
+	}
+	return nil
+}
+
+// auditJSON returns the values of columns as a JSON document, or NULL for no
+// values. Text stored as bytes is written as a string.
+func auditJSON(values map[string]interface{}) (sql.NullString, error) {
+	if values == nil {
+		return sql.NullString{}, nil
+	}
+	doc := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		if b, ok := v.([]byte); ok && utf8.Valid(b) {
+			v = string(b)
+		}
+		doc[k] = v
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
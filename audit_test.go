@@ -0,0 +1,262 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+type AuditedNote struct {
+	ID        int        `db:"id"`
+	Body      string     `db:"body"`
+	DeletedAt nulls.Time `db:"deleted_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+func (AuditedNote) AuditTable() string {
+	return "audited_notes_history"
+}
+
+type SharedAuditedNote struct {
+	ID        int        `db:"id"`
+	Body      string     `db:"body"`
+	DeletedAt nulls.Time `db:"deleted_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+func (SharedAuditedNote) TableName() string {
+	return "audited_notes"
+}
+
+func (SharedAuditedNote) AuditTable() string {
+	return AuditLogTable
+}
+
+type MisauditedNote struct {
+	ID        int        `db:"id"`
+	Body      string     `db:"body"`
+	DeletedAt nulls.Time `db:"deleted_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+func (MisauditedNote) TableName() string {
+	return "audited_notes"
+}
+
+func (MisauditedNote) AuditTable() string {
+	return "missing_history"
+}
+
+type auditEntry struct {
+	ID        int          `db:"id"`
+	Table     string       `db:"table_name"`
+	RecordID  string       `db:"record_id"`
+	Operation string       `db:"operation"`
+	OldValues nulls.String `db:"old_values"`
+	NewValues nulls.String `db:"new_values"`
+	Actor     nulls.String `db:"actor"`
+}
+
+func (e auditEntry) values(t *testing.T, doc nulls.String) map[string]interface{} {
+	if !doc.Valid {
+		return nil
+	}
+	values := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(doc.String), &values))
+	return values
+}
+
+func Test_isAuditable(t *testing.T) {
+	r := require.New(t)
+
+	r.True(isAuditable(&AuditedNote{}))
+	r.True(isAuditable(&[]AuditedNote{}))
+	r.True(isAuditable([]*AuditedNote{}))
+	r.False(isAuditable(&User{}))
+	r.False(isAuditable(&[]User{}))
+}
+
+func Test_AuditActor(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("", AuditActor(context.Background()))
+	r.Equal("mark", AuditActor(WithAuditActor(context.Background(), "mark")))
+}
+
+func Test_auditJSON(t *testing.T) {
+	r := require.New(t)
+
+	doc, err := auditJSON(map[string]interface{}{"body": []byte("first")})
+	r.NoError(err)
+	r.Equal(sql.NullString{String: `{"body":"first"}`, Valid: true}, doc)
+
+	// NULL values are typed, so drivers such as YDB can bind them.
+	doc, err = auditJSON(nil)
+	r.NoError(err)
+	r.False(doc.Valid)
+	_, _, err = ydbBind("SELECT $p1", []interface{}{doc})
+	r.NoError(err)
+}
+
+func Test_Audit(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+		tx = tx.WithContext(WithAuditActor(context.Background(), "mark"))
+
+		n := &AuditedNote{Body: "first"}
+		r.NoError(tx.Create(n))
+		n.Body = "second"
+		r.NoError(tx.Update(n))
+		r.NoError(tx.Destroy(n))
+		r.NoError(tx.HardDestroy(n))
+
+		entries := []auditEntry{}
+		r.NoError(tx.RawQuery("SELECT id, table_name, record_id, operation, old_values, new_values, actor FROM audited_notes_history WHERE record_id = ? ORDER BY id", n.ID).All(&entries))
+		r.Len(entries, 4)
+
+		for _, e := range entries {
+			r.Equal("audited_notes", e.Table)
+			r.Equal("mark", e.Actor.String)
+		}
+
+		e := entries[0]
+		r.Equal(AuditCreate, e.Operation)
+		r.Nil(e.values(t, e.OldValues))
+		r.Equal("first", e.values(t, e.NewValues)["body"])
+
+		e = entries[1]
+		r.Equal(AuditUpdate, e.Operation)
+		r.Equal("first", e.values(t, e.OldValues)["body"])
+		r.Equal("second", e.values(t, e.NewValues)["body"])
+
+		e = entries[2]
+		r.Equal(AuditDestroy, e.Operation)
+		r.Nil(e.values(t, e.OldValues)["deleted_at"])
+		r.NotNil(e.values(t, e.NewValues)["deleted_at"])
+
+		e = entries[3]
+		r.Equal(AuditDestroy, e.Operation)
+		r.Equal("second", e.values(t, e.OldValues)["body"])
+		r.False(e.NewValues.Valid)
+	})
+}
+
+func Test_Audit_Upsert(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		n := &AuditedNote{Body: "first"}
+		r.NoError(tx.Upsert(n, []string{"id"}))
+		n.Body = "second"
+		r.NoError(tx.Upsert(n, []string{"id"}))
+		n.Body = "ignored"
+		r.NoError(tx.UpsertIgnore(n, "id"))
+		r.Error(tx.UpsertIgnore(&AuditedNote{Body: "any"}))
+
+		entries := []auditEntry{}
+		r.NoError(tx.RawQuery("SELECT id, table_name, record_id, operation, old_values, new_values, actor FROM audited_notes_history WHERE record_id = ? ORDER BY id", n.ID).All(&entries))
+		r.Len(entries, 2)
+
+		e := entries[0]
+		r.Equal(AuditCreate, e.Operation)
+		r.Nil(e.values(t, e.OldValues))
+		r.Equal("first", e.values(t, e.NewValues)["body"])
+
+		e = entries[1]
+		r.Equal(AuditUpdate, e.Operation)
+		r.Equal("first", e.values(t, e.OldValues)["body"])
+		r.Equal("second", e.values(t, e.NewValues)["body"])
+	})
+}
+
+func Test_Audit_Query(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		notes := []AuditedNote{{Body: "first"}, {Body: "second"}, {Body: "third"}}
+		r.NoError(tx.CreateMany(&notes))
+
+		n, err := tx.Where("body <> ?", "third").UpdateQuery(&AuditedNote{Body: "updated"}, "body")
+		r.NoError(err)
+		r.EqualValues(2, n)
+		r.NoError(tx.Where("body = ?", "updated").Delete(&AuditedNote{}))
+		r.NoError(tx.Where("body = ?", "third").Delete(&AuditedNote{}))
+		r.NoError(tx.Unscoped().Where("body = ?", "third").Delete(&AuditedNote{}))
+
+		entries := []auditEntry{}
+		r.NoError(tx.RawQuery("SELECT id, table_name, record_id, operation, old_values, new_values, actor FROM audited_notes_history WHERE operation <> ? ORDER BY id", AuditCreate).All(&entries))
+		r.Len(entries, 6)
+
+		for _, e := range entries[:2] {
+			r.Equal(AuditUpdate, e.Operation)
+			r.NotEqual("updated", e.values(t, e.OldValues)["body"])
+			r.Equal("updated", e.values(t, e.NewValues)["body"])
+		}
+		for _, e := range entries[2:5] {
+			r.Equal(AuditDestroy, e.Operation)
+			r.Nil(e.values(t, e.OldValues)["deleted_at"])
+			r.NotNil(e.values(t, e.NewValues)["deleted_at"])
+		}
+
+		e := entries[5]
+		r.Equal(AuditDestroy, e.Operation)
+		r.Equal(fmt.Sprint(notes[2].ID), e.RecordID)
+		r.NotNil(e.values(t, e.OldValues)["deleted_at"])
+		r.False(e.NewValues.Valid)
+	})
+}
+
+func Test_Audit_Shared(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		notes := []SharedAuditedNote{{Body: "first"}, {Body: "second"}}
+		r.NoError(tx.CreateMany(&notes))
+
+		entries := []auditEntry{}
+		r.NoError(tx.RawQuery("SELECT id, table_name, record_id, operation, old_values, new_values, actor FROM audit_log WHERE table_name = ? ORDER BY id", "audited_notes").All(&entries))
+		r.Len(entries, 2)
+		for _, e := range entries {
+			r.Equal(AuditCreate, e.Operation)
+			r.False(e.Actor.Valid)
+		}
+	})
+}
+
+func Test_Audit_SameTransaction(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	before, err := PDB.Count("audited_notes")
+	r.NoError(err)
+
+	n := &MisauditedNote{Body: "lost"}
+	r.Error(PDB.Create(n))
+
+	after, err := PDB.Count("audited_notes")
+	r.NoError(err)
+	r.Equal(before, after)
+}
@@ -20,6 +20,8 @@ type AfterEagerFindable interface {
 }
 
 func (m *Model) afterFind(c *Connection, eager bool) error {
+	m.trackAll()
+
 	if eager {
 		if x, ok := m.Value.(AfterEagerFindable); ok {
 			if err := x.AfterEagerFind(c); err != nil {
@@ -52,3 +52,11 @@ type belongsToThroughClause struct {
 }
 
 type belongsToThroughClauses []belongsToThroughClause
+
+// setClause combines the rows of a query with those of another one.
+type setClause struct {
+	Operator string
+	Query    *Query
+}
+
+type setClauses []setClause
@@ -0,0 +1,58 @@
+package columns
+
+import (
+	"reflect"
+)
+
+// structFields returns the fields of the struct passed in, including the
+// fields of embedded structs, in declaration order.
+func structFields(s interface{}) []reflect.StructField {
+	st := structType(s)
+	if st == nil || st.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []reflect.StructField
+	var findFields func(t reflect.Type)
+	findFields = func(t reflect.Type) {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous {
+				findFields(f.Type)
+				continue
+			}
+			fields = append(fields, f)
+		}
+	}
+	findFields(st)
+	return fields
+}
+
+// structType returns the type of the value passed in, without the pointers,
+// slices and arrays around it.
+func structType(s interface{}) reflect.Type {
+	st := reflect.TypeOf(s)
+	if st == nil {
+		return nil
+	}
+	for st.Kind() == reflect.Ptr || st.Kind() == reflect.Slice || st.Kind() == reflect.Array {
+		st = st.Elem()
+	}
+	return st
+}
+
+// columnFor returns the field and column names of the given field, or empty
+// names if the field is not mapped to a column.
+func columnFor(f reflect.StructField) (field, column string) {
+	tag := TagsFor(f).Find("db")
+	if tag.Empty() || tag.Ignored() {
+		return "", ""
+	}
+	return f.Name, tag.Value
+}
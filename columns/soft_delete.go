@@ -0,0 +1,64 @@
+package columns
+
+import (
+	"reflect"
+	"sync"
+)
+
+// softDeleteCache holds the field and column names found by SoftDeleteFor
+// for each struct type, since they are needed by every query.
+var softDeleteCache = map[reflect.Type][2]string{}
+var softDeleteCacheMutex = sync.RWMutex{}
+
+// SoftDeleteFor returns the field and column names which mark a row of the
+// struct passed in as deleted. It is the field tagged with
+// `soft_delete:"true"`, or else the `DeletedAt` field if it is nullable, such
+// as a nulls.Time, sql.NullTime or *time.Time, unless it is tagged with
+// `soft_delete:"-"`. Empty names are returned if the struct has neither.
+func SoftDeleteFor(s interface{}) (field, column string) {
+	st := structType(s)
+	if st == nil {
+		return "", ""
+	}
+	softDeleteCacheMutex.RLock()
+	names, ok := softDeleteCache[st]
+	softDeleteCacheMutex.RUnlock()
+	if ok {
+		return names[0], names[1]
+	}
+
+	field, column = softDeleteFor(s)
+	softDeleteCacheMutex.Lock()
+	softDeleteCache[st] = [2]string{field, column}
+	softDeleteCacheMutex.Unlock()
+	return field, column
+}
+
+func softDeleteFor(s interface{}) (field, column string) {
+	for _, f := range structFields(s) {
+		switch f.Tag.Get("soft_delete") {
+		case "", "-", "false":
+		default:
+			return columnFor(f)
+		}
+	}
+	for _, f := range structFields(s) {
+		if f.Name == "DeletedAt" && f.Tag.Get("soft_delete") == "" && nullable(f.Type) {
+			return columnFor(f)
+		}
+	}
+	return "", ""
+}
+
+// nullable tells if a field of the given type can hold NULL, as pointers and
+// structs with a `Valid` flag, such as sql.NullTime, can.
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return true
+	case reflect.Struct:
+		f, ok := t.FieldByName("Valid")
+		return ok && f.Type.Kind() == reflect.Bool
+	}
+	return false
+}
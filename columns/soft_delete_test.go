@@ -0,0 +1,67 @@
+package columns_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/pop/v6/columns"
+	"github.com/stretchr/testify/require"
+)
+
+type softDeleted struct {
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+type softDeletedTag struct {
+	RemovedAt *time.Time `db:"removed_at" soft_delete:"true"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+type softDeletedEmbedded struct {
+	softDeletedTag
+	Name string `db:"name"`
+}
+
+type softDeletedNotNull struct {
+	DeletedAt time.Time `db:"deleted_at"`
+}
+
+type softDeletedNotNullTag struct {
+	DeletedAt time.Time `db:"deleted_at" soft_delete:"true"`
+}
+
+type softDeletedDisabled struct {
+	DeletedAt *time.Time `db:"deleted_at" soft_delete:"-"`
+}
+
+func Test_SoftDeleteFor(t *testing.T) {
+	r := require.New(t)
+
+	field, column := columns.SoftDeleteFor(&softDeleted{})
+	r.Equal("DeletedAt", field)
+	r.Equal("deleted_at", column)
+
+	field, column = columns.SoftDeleteFor([]softDeletedTag{})
+	r.Equal("RemovedAt", field)
+	r.Equal("removed_at", column)
+
+	field, column = columns.SoftDeleteFor(softDeletedEmbedded{})
+	r.Equal("RemovedAt", field)
+	r.Equal("removed_at", column)
+
+	field, column = columns.SoftDeleteFor(&softDeletedNotNull{})
+	r.Empty(field)
+	r.Empty(column)
+
+	field, column = columns.SoftDeleteFor(&softDeletedNotNullTag{})
+	r.Equal("DeletedAt", field)
+	r.Equal("deleted_at", column)
+
+	field, column = columns.SoftDeleteFor(&softDeletedDisabled{})
+	r.Empty(field)
+	r.Empty(column)
+
+	field, column = columns.SoftDeleteFor(&foo{})
+	r.Empty(field)
+	r.Empty(column)
+}
@@ -0,0 +1,22 @@
+package columns
+
+// VersionFor returns the field and column names used for the optimistic
+// locking of the struct passed in. It is the field tagged with
+// `version:"true"`, or else the field of the `lock_version` column unless it
+// is tagged with `version:"-"`. Empty names are returned if the struct has
+// neither.
+func VersionFor(s interface{}) (field, column string) {
+	for _, f := range structFields(s) {
+		switch f.Tag.Get("version") {
+		case "", "-", "false":
+		default:
+			return columnFor(f)
+		}
+	}
+	for _, f := range structFields(s) {
+		if field, column := columnFor(f); column == "lock_version" && f.Tag.Get("version") == "" {
+			return field, column
+		}
+	}
+	return "", ""
+}
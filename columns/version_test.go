@@ -0,0 +1,41 @@
+package columns_test
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/pop/v6/columns"
+	"github.com/stretchr/testify/require"
+)
+
+type versioned struct {
+	LockVersion int `db:"lock_version"`
+}
+
+type versionedTag struct {
+	Revision    int `db:"revision" version:"true"`
+	LockVersion int `db:"lock_version"`
+}
+
+type versionedDisabled struct {
+	LockVersion int `db:"lock_version" version:"-"`
+}
+
+func Test_VersionFor(t *testing.T) {
+	r := require.New(t)
+
+	field, column := columns.VersionFor(&versioned{})
+	r.Equal("LockVersion", field)
+	r.Equal("lock_version", column)
+
+	field, column = columns.VersionFor([]versionedTag{})
+	r.Equal("Revision", field)
+	r.Equal("revision", column)
+
+	field, column = columns.VersionFor(&versionedDisabled{})
+	r.Empty(field)
+	r.Empty(column)
+
+	field, column = columns.VersionFor(&foo{})
+	r.Empty(field)
+	r.Empty(column)
+}
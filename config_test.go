@@ -3,6 +3,7 @@ package pop
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gobuffalo/envy"
 	"github.com/stretchr/testify/require"
@@ -62,3 +63,31 @@ mysql:
 	r.NoError(err)
 	r.False(conns["mysql"].Unsafe)
 }
+
+func Test_ParseConfigReplicas(t *testing.T) {
+	r := require.New(t)
+	config := strings.NewReader(`
+postgres:
+  url: "postgres://postgres@primary:5432/pop_test"
+  replicas:
+    - "postgres://postgres@replica1:5432/pop_test"
+    - "postgres://postgres@replica2:5432/pop_test"
+  replica_strategy: least_busy`)
+	conns, err := ParseConfig(config)
+	r.NoError(err)
+	r.Equal([]string{"postgres://postgres@replica1:5432/pop_test", "postgres://postgres@replica2:5432/pop_test"}, conns["postgres"].Replicas)
+	r.Equal(ReplicaLeastBusy, conns["postgres"].ReplicaStrategy)
+}
+
+func Test_ParseConfigSlowQuery(t *testing.T) {
+	r := require.New(t)
+	config := strings.NewReader(`
+postgres:
+  url: "postgres://postgres@primary:5432/pop_test"
+  slow_query_threshold: 200ms
+  slow_query_sample_rate: 0.1`)
+	conns, err := ParseConfig(config)
+	r.NoError(err)
+	r.Equal(200*time.Millisecond, conns["postgres"].SlowQueryThreshold)
+	r.Equal(0.1, conns["postgres"].SlowQuerySampleRate)
+}
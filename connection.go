@@ -13,6 +13,7 @@ import (
 	"github.com/gobuffalo/pop/v6/internal/defaults"
 	"github.com/gobuffalo/pop/v6/internal/randx"
 	"github.com/gobuffalo/pop/v6/logging"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // Connections contains all available connections
@@ -20,13 +21,18 @@ var Connections = map[string]*Connection{}
 
 // Connection represents all necessary details to talk with a datastore
 type Connection struct {
-	ID          string
-	Store       store
-	Dialect     dialect
-	Elapsed     int64
-	TX          *Tx
-	eager       bool
-	eagerFields []string
+	ID           string
+	Store        Store
+	Dialect      Dialect
+	Elapsed      int64
+	TX           *Tx
+	eager        bool
+	eagerFields  []string
+	replicas     *replicaSet
+	usePrimary   bool
+	interceptors *interceptorChain
+	timeout      time.Duration
+	poolMetrics  metric.Registration
 }
 
 func (c *Connection) String() string {
@@ -67,6 +73,12 @@ func NewConnection(deets *ConnectionDetails) (*Connection, error) {
 	}
 	c := &Connection{}
 	c.setID()
+	if deets.Logger != nil {
+		c.UseLogger(deets.Logger)
+	}
+	if deets.SlowQueryThreshold > 0 {
+		c.Use(slowQueryInterceptor(deets.SlowQueryThreshold, deets.SlowQuerySampleRate))
+	}
 
 	if nc, ok := newConnection[deets.Dialect]; ok {
 		c.Dialect, err = nc(deets)
@@ -131,6 +143,18 @@ func (c *Connection) Open() error {
 		db = db.Unsafe()
 	}
 	c.Store = &dB{db}
+	if d, ok := c.Dialect.(storeWrapper); ok {
+		c.Store = d.WrapStore(c.Store)
+	}
+	c.Store = c.interceptors.wrap(c.Store, c.ID, 0)
+
+	if len(details.Replicas) > 0 {
+		if c.replicas, err = openReplicas(details); err != nil {
+			c.Store.Close()
+			c.Store = nil
+			return err
+		}
+	}
 
 	if d, ok := c.Dialect.(afterOpenable); ok {
 		if err := d.AfterOpen(c); err != nil {
@@ -143,17 +167,38 @@ func (c *Connection) Open() error {
 
 // Close destroys an active datasource connection
 func (c *Connection) Close() error {
+	if c.poolMetrics != nil {
+		if err := c.poolMetrics.Unregister(); err != nil {
+			return fmt.Errorf("couldn't unregister connection pool metrics: %w", err)
+		}
+		c.poolMetrics = nil
+	}
 	if err := c.Store.Close(); err != nil {
 		return fmt.Errorf("couldn't close connection: %w", err)
 	}
 	c.Store = nil
+	if c.replicas != nil {
+		err := c.replicas.Close()
+		c.replicas = nil
+		if err != nil {
+			return fmt.Errorf("couldn't close replica connection: %w", err)
+		}
+	}
 	return nil
 }
 
 // Transaction will start a new transaction on the connection. If the inner function
 // returns an error then the transaction will be rolled back, otherwise the transaction
 // will automatically commit at the end.
+//
+// If the connection is already in a transaction, a savepoint is created instead,
+// so an error of the inner function only rolls back the work done since the
+// savepoint. The enclosing transaction continues either way.
 func (c *Connection) Transaction(fn func(tx *Connection) error) error {
+	if c.TX != nil {
+		return c.savepoint(fn)
+	}
+
 	return c.Dialect.Lock(func() (err error) {
 		var dberr error
 
@@ -161,14 +206,14 @@ func (c *Connection) Transaction(fn func(tx *Connection) error) error {
 		if err != nil {
 			return err
 		}
-		txlog(logging.SQL, cn, "BEGIN Transaction ---")
+		cn.logDirect("BEGIN Transaction ---")
 
 		defer func() {
 			if ex := recover(); ex != nil {
-				txlog(logging.SQL, cn, "ROLLBACK Transaction (inner function panic) ---")
+				cn.logDirect("ROLLBACK Transaction (inner function panic) ---")
 				dberr = cn.TX.Rollback()
 				if dberr != nil {
-					txlog(logging.Error, cn, "database error while inner panic rollback: %w", dberr)
+					cn.log(logging.Error, "database error while inner panic rollback: %v", dberr)
 				}
 				panic(ex)
 			}
@@ -176,10 +221,10 @@ func (c *Connection) Transaction(fn func(tx *Connection) error) error {
 
 		err = fn(cn)
 		if err != nil {
-			txlog(logging.SQL, cn, "ROLLBACK Transaction ---")
+			cn.logDirect("ROLLBACK Transaction ---")
 			dberr = cn.TX.Rollback()
 		} else {
-			txlog(logging.SQL, cn, "END Transaction ---")
+			cn.logDirect("END Transaction ---")
 			dberr = cn.TX.Commit()
 		}
 
@@ -192,6 +237,130 @@ func (c *Connection) Transaction(fn func(tx *Connection) error) error {
 
 }
 
+// savepoint runs fn within a savepoint of the current transaction, which is
+// rolled back to if fn fails and released otherwise.
+func (c *Connection) savepoint(fn func(tx *Connection) error) (err error) {
+	d, ok := c.Dialect.(savepointer)
+	if !ok {
+		return fmt.Errorf("nested transactions are not supported by the %s dialect", c.Dialect.Name())
+	}
+	name := c.TX.nextSavepoint()
+	exec := func(op string) error {
+		stmt, err := d.SavepointSQL(op, name)
+		if err != nil {
+			return err
+		}
+		_, err = GenericExec(c, stmt)
+		return err
+	}
+
+	if err := exec("SAVEPOINT"); err != nil {
+		return fmt.Errorf("couldn't create savepoint: %w", err)
+	}
+
+	defer func() {
+		if ex := recover(); ex != nil {
+			if dberr := exec("ROLLBACK TO SAVEPOINT"); dberr != nil {
+				c.log(logging.Error, "database error while inner panic rollback to savepoint: %v", dberr)
+			}
+			panic(ex)
+		}
+	}()
+
+	if err = fn(c); err != nil {
+		if dberr := exec("ROLLBACK TO SAVEPOINT"); dberr != nil {
+			return fmt.Errorf("database error on rolling back to savepoint: %w", dberr)
+		}
+		return err
+	}
+
+	if dberr := exec("RELEASE SAVEPOINT"); dberr != nil {
+		return fmt.Errorf("database error on releasing savepoint: %w", dberr)
+	}
+	return nil
+}
+
+// RetryPolicy configures how TransactionWithRetry retries a transaction.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the transaction is run.
+	// Defaults to 3.
+	MaxAttempts int
+	// Backoff is the wait before the second attempt, doubled before each
+	// following one, with some jitter. Defaults to 0, no wait.
+	Backoff time.Duration
+}
+
+// wait sleeps before the attempt following the given one.
+func (p RetryPolicy) wait(attempt int) {
+	if p.Backoff <= 0 {
+		return
+	}
+	d := p.Backoff << (attempt - 1)
+	time.Sleep(d/2 + time.Duration(rand.Int63n(int64(d/2)+1)))
+}
+
+// TransactionWithRetry runs fn in a transaction like Transaction, and runs
+// it again in a fresh transaction when the transaction fails with an error
+// the dialect reports as retryable, such as a serialization failure or a
+// deadlock. fn must therefore be safe to run several times.
+//
+// On CockroachDB, fn is retried within the same transaction, rolled back to
+// the cockroach_restart savepoint. If the connection is already in a
+// transaction, fn runs once in a savepoint, since only the whole transaction
+// could be retried.
+//
+//	err := c.TransactionWithRetry(func(tx *pop.Connection) error {
+//		...
+//	}, pop.RetryPolicy{MaxAttempts: 5, Backoff: 10 * time.Millisecond})
+func (c *Connection) TransactionWithRetry(fn func(tx *Connection) error, policy RetryPolicy) error {
+	d, ok := c.Dialect.(retryable)
+	if !ok || c.TX != nil {
+		return c.Transaction(fn)
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 3
+	}
+	if c.Dialect.Name() == nameCockroach {
+		return c.Transaction(func(tx *Connection) error {
+			return tx.cockroachRetry(d, fn, policy)
+		})
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := c.Transaction(fn)
+		if err == nil || attempt >= policy.MaxAttempts || !d.IsRetryable(err) {
+			return err
+		}
+		c.log(logging.Warn, "retrying transaction (attempt %d of %d) after: %v", attempt+1, policy.MaxAttempts, err)
+		policy.wait(attempt)
+	}
+}
+
+// cockroachRetry runs fn in the transaction of the connection with the
+// client-side retry protocol of CockroachDB: fn runs after a cockroach_restart
+// savepoint, which is rolled back to before retrying and released on success.
+func (c *Connection) cockroachRetry(d retryable, fn func(tx *Connection) error, policy RetryPolicy) error {
+	if _, err := GenericExec(c, "SAVEPOINT cockroach_restart"); err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn(c)
+		if err == nil {
+			if _, err = GenericExec(c, "RELEASE SAVEPOINT cockroach_restart"); err == nil {
+				return nil
+			}
+		}
+		if attempt >= policy.MaxAttempts || !d.IsRetryable(err) {
+			return err
+		}
+		c.log(logging.Warn, "retrying transaction (attempt %d of %d) after: %v", attempt+1, policy.MaxAttempts, err)
+		if _, rerr := GenericExec(c, "ROLLBACK TO SAVEPOINT cockroach_restart"); rerr != nil {
+			return fmt.Errorf("couldn't restart transaction: %w", rerr)
+		}
+		policy.wait(attempt)
+	}
+}
+
 // Rollback will open a new transaction and automatically rollback that transaction
 // when the inner function returns, regardless. This can be useful for tests, etc...
 func (c *Connection) Rollback(fn func(tx *Connection)) error {
@@ -200,9 +369,9 @@ func (c *Connection) Rollback(fn func(tx *Connection)) error {
 	if err != nil {
 		return err
 	}
-	txlog(logging.SQL, cn, "BEGIN Transaction for Rollback ---")
+	cn.logDirect("BEGIN Transaction for Rollback ---")
 	fn(cn)
-	txlog(logging.SQL, cn, "ROLLBACK Transaction as planned ---")
+	cn.logDirect("ROLLBACK Transaction as planned ---")
 	return cn.TX.Rollback()
 }
 
@@ -225,12 +394,19 @@ func (c *Connection) NewTransactionContextOptions(ctx context.Context, options *
 			return cn, fmt.Errorf("couldn't start a new transaction: %w", err)
 		}
 
+		var txStore Store = tx
+		if d, ok := c.Dialect.(storeWrapper); ok {
+			txStore = d.WrapStore(txStore)
+		}
+
 		cn = &Connection{
-			Store:   contextStore{store: tx, ctx: ctx},
-			Dialect: c.Dialect,
-			TX:      tx,
+			Dialect:      c.Dialect,
+			TX:           tx,
+			interceptors: c.interceptors,
+			timeout:      c.timeout,
 		}
 		cn.setID()
+		cn.Store = contextStore{Store: c.interceptors.wrap(txStore, cn.ID, tx.ID), ctx: ctx, timeout: c.timeout}
 	} else {
 		cn = c
 	}
@@ -241,21 +417,38 @@ func (c *Connection) NewTransactionContextOptions(ctx context.Context, options *
 func (c *Connection) WithContext(ctx context.Context) *Connection {
 	cn := c.copy()
 	cn.Store = contextStore{
-		store: cn.Store,
+		Store: cn.Store,
 		ctx:   ctx,
 	}
 	return cn
 }
 
+// withTimeout returns a copy of the connection whose statements are bounded
+// to the given duration.
+func (c *Connection) withTimeout(d time.Duration) *Connection {
+	cn := c.copy()
+	cn.timeout = d
+	cn.Store = contextStore{
+		Store:   cn.Store,
+		ctx:     cn.Context(),
+		timeout: d,
+	}
+	return cn
+}
+
 func (c *Connection) copy() *Connection {
 	// TODO: checkme. it copies and creates a new Connection (and a new ID)
 	// with the same TX which could make confusions and complexity in usage.
 	// related PRs: #72/#73, #79/#80, and #497
 
 	cn := &Connection{
-		Store:   c.Store,
-		Dialect: c.Dialect,
-		TX:      c.TX,
+		Store:        c.Store,
+		Dialect:      c.Dialect,
+		TX:           c.TX,
+		replicas:     c.replicas,
+		usePrimary:   c.usePrimary,
+		interceptors: c.interceptors,
+		timeout:      c.timeout,
 	}
 	cn.setID(c.ID) // ID of the source as a seed
 
@@ -1,7 +1,9 @@
 package pop
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -103,3 +105,55 @@ func Test_ConnectionDetails_OptionsString_Postgres(t *testing.T) {
 	r.Equal("sslmode=require", cd.OptionsString(""))
 	r.Equal("migrations", cd.MigrationTableName())
 }
+
+func Test_ConnectionDetails_StatementTimeout(t *testing.T) {
+	table := []struct {
+		dialect string
+		timeout string
+		want    time.Duration
+		option  string
+	}{
+		{"postgres", "5s", 5 * time.Second, "statement_timeout=5s"},
+		{"cockroach", "250", 250 * time.Millisecond, "statement_timeout=250"},
+		{"mysql", "1.5s", 1500 * time.Millisecond, "max_execution_time=1500"},
+		{"mariadb", "1.5s", 1500 * time.Millisecond, "max_statement_time=1.5"},
+		{"sqlite3", "10s", 10 * time.Second, "_busy_timeout=10000"},
+		{"sqlite3", "soon", 0, "_busy_timeout=5000"},
+	}
+	for _, tt := range table {
+		t.Run(tt.dialect, func(t *testing.T) {
+			r := require.New(t)
+
+			cd := &ConnectionDetails{
+				Dialect:  tt.dialect,
+				Database: "database",
+				Options:  map[string]string{"statement_timeout": tt.timeout},
+			}
+			r.NoError(cd.Finalize())
+			r.Equal(tt.want, cd.statementTimeout())
+			r.Equal(tt.timeout, cd.Options["statement_timeout"])
+			r.Contains(cd.OptionsString(""), tt.option)
+			if !strings.HasPrefix(tt.option, "statement_timeout=") {
+				r.NotContains(cd.OptionsString(""), "statement_timeout")
+			}
+		})
+	}
+
+	// the options set by the user are kept
+	cd := &ConnectionDetails{
+		Dialect:  "sqlite3",
+		Database: "database",
+		Options:  map[string]string{"statement_timeout": "10s", "_busy_timeout": "100"},
+	}
+	r := require.New(t)
+	r.NoError(cd.Finalize())
+	r.Equal("100", cd.Options["_busy_timeout"])
+
+	cd = &ConnectionDetails{
+		Dialect:  "mysql",
+		Database: "database",
+		Options:  map[string]string{"statement_timeout": "10s", "max_execution_time": "100"},
+	}
+	r.NoError(cd.Finalize())
+	r.Equal("100", cd.Options["max_execution_time"])
+}
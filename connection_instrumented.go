@@ -57,6 +57,19 @@ func instrumentDriver(deets *ConnectionDetails, defaultDriverName string) (drive
 			return "", "", err
 		}
 		newDriverName = instrumentedDriverName + "-" + nameSQLite3
+	default:
+		// Dialects without a built-in driver, e.g. registered with
+		// `RegisterDialect`, are wrapped with whatever driver is
+		// registered under the driver name.
+		db, err := sql.Open(driverName, "")
+		if err != nil {
+			return "", "", err
+		}
+		dr = db.Driver()
+		if err := db.Close(); err != nil {
+			return "", "", err
+		}
+		newDriverName = instrumentedDriverName + "-" + driverName
 	}
 
 	sqlDriverLock.Lock()
@@ -83,7 +96,7 @@ func instrumentDriver(deets *ConnectionDetails, defaultDriverName string) (drive
 // translate arguments (e.g. `?` to `$1`) in SQL queries. Because we use
 // a custom driver name when using instrumentation, this detection would fail
 // otherwise.
-func openPotentiallyInstrumentedConnection(c dialect, dsn string) (*sqlx.DB, error) {
+func openPotentiallyInstrumentedConnection(c Dialect, dsn string) (*sqlx.DB, error) {
 	driverName, dialect, err := instrumentDriver(c.Details(), c.DefaultDriver())
 	if err != nil {
 		return nil, err
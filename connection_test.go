@@ -6,6 +6,7 @@ package pop
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -145,3 +146,47 @@ func Test_Connection_Transaction(t *testing.T) {
 		})
 	})
 }
+
+func Test_Connection_Replicas(t *testing.T) {
+	r := require.New(t)
+
+	db := filepath.Join(t.TempDir(), "replicas.db")
+	for _, strategy := range []string{ReplicaRoundRobin, ReplicaLeastBusy} {
+		c, err := NewConnection(&ConnectionDetails{
+			URL:             "sqlite://" + db,
+			Replicas:        []string{"sqlite://" + db, "sqlite://" + db},
+			ReplicaStrategy: strategy,
+		})
+		r.NoError(err)
+		r.NoError(c.Open())
+		r.Len(c.replicas.stores, 2)
+		if strategy == ReplicaLeastBusy {
+			r.Len(c.replicas.dbs, 2)
+		}
+
+		r.NoError(c.RawQuery("CREATE TABLE IF NOT EXISTS composers (id integer primary key, name text, created_at datetime, updated_at datetime)").Exec())
+		r.NoError(c.RawQuery("INSERT INTO composers (name) VALUES (?)", strategy).Exec())
+
+		count, err := c.Where("name = ?", strategy).Count(&Composer{})
+		r.NoError(err)
+		r.Equal(1, count)
+		count, err = c.UsePrimary().Where("name = ?", strategy).Count(&Composer{})
+		r.NoError(err)
+		r.Equal(1, count)
+
+		r.NoError(c.Close())
+		r.Nil(c.replicas)
+	}
+}
+
+func Test_storeDB(t *testing.T) {
+	r := require.New(t)
+
+	db := &dB{}
+	found, ok := storeDB(contextStore{Store: interceptedStore{Store: ydbStore{Store: db}}})
+	r.True(ok)
+	r.Same(db, found)
+
+	_, ok = storeDB(&Tx{})
+	r.False(ok)
+}
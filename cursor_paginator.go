@@ -0,0 +1,319 @@
+package pop
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+var _ paginable = CursorPaginator{}
+
+// CursorPaginator is a type used to represent the keyset pagination of
+// records from the database. Contrary to Paginator, it selects the rows
+// following the last row of the previous page instead of skipping an offset,
+// and does not count the total number of records, so deep pages of large
+// tables stay fast.
+type CursorPaginator struct {
+	// Cursor of the current page, empty for the first page
+	Cursor string `json:"cursor"`
+	// Number of results you want per page
+	PerPage int `json:"per_page"`
+	// Cursor of the next page, empty on the last page
+	NextCursor string `json:"next_cursor"`
+	// Cursor of the previous page, empty on the first page
+	PrevCursor string `json:"prev_cursor"`
+	// Total records returns, will be <= PerPage
+	CurrentEntriesSize int `json:"current_entries_size"`
+
+	order []cursorColumn
+}
+
+// Paginate implements the paginable interface.
+func (p CursorPaginator) Paginate() string {
+	b, _ := json.Marshal(p)
+	return string(b)
+}
+
+func (p CursorPaginator) String() string {
+	return p.Paginate()
+}
+
+// cursorColumn is a column the rows of a cursor paginated query are ordered by.
+type cursorColumn struct {
+	Name string
+	Desc bool
+}
+
+// cursorToken is the decoded content of a cursor.
+type cursorToken struct {
+	// Prev is true for cursors pointing backwards, to the previous page.
+	Prev bool `json:"p,omitempty"`
+	// Values of the order columns of the row the page starts after.
+	Values []json.RawMessage `json:"v"`
+}
+
+var cursorColumnRegex = regexp.MustCompile(`^[A-Za-z0-9_."]+$`)
+
+// PaginateByCursor paginates records returned from the database by keyset.
+// The rows are ordered by the given columns, each optionally followed by
+// `asc` or `desc`, and by the ID if it is not among them. The order columns
+// must not be nullable. An empty cursor selects the first page.
+//
+//	q := c.PaginateByCursor(req.URL.Query().Get("cursor"), 20, "created_at desc")
+//	q.All(&[]User{})
+//	q.CursorPaginator.NextCursor
+func (c *Connection) PaginateByCursor(cursor string, perPage int, orderColumns ...string) *Query {
+	return Q(c).PaginateByCursor(cursor, perPage, orderColumns...)
+}
+
+// PaginateByCursor paginates records returned from the database by keyset.
+// The rows are ordered by the given columns, each optionally followed by
+// `asc` or `desc`, and by the ID if it is not among them. The order columns
+// must not be nullable. An empty cursor selects the first page.
+//
+//	q = q.PaginateByCursor(cursor, 20, "created_at desc")
+//	q.All(&[]User{})
+//	q.CursorPaginator.NextCursor
+func (q *Query) PaginateByCursor(cursor string, perPage int, orderColumns ...string) *Query {
+	if perPage < 1 {
+		perPage = PaginatorPerPageDefault
+	}
+	p := &CursorPaginator{Cursor: cursor, PerPage: perPage}
+	for _, c := range orderColumns {
+		parts := strings.Fields(c)
+		col := cursorColumn{}
+		if len(parts) > 0 {
+			col.Name = parts[0]
+		}
+		if len(parts) > 1 {
+			col.Desc = strings.EqualFold(parts[1], "desc")
+		}
+		p.order = append(p.order, col)
+	}
+	q.CursorPaginator = p
+	return q
+}
+
+// selectByCursor selects the page of the cursor paginator into models and
+// sets the cursors of the previous and next pages.
+func (q *Query) selectByCursor(models *Model) error {
+	p := q.CursorPaginator
+	if q.RawSQL.Fragment != "" || len(q.orderClauses) > 0 || q.limitResults > 0 || q.Paginator != nil {
+		return errors.New("cursor pagination can not be combined with Order, Limit, Paginate or RawQuery")
+	}
+
+	order := append([]cursorColumn{}, p.order...)
+	hasID := false
+	for _, c := range order {
+		if !cursorColumnRegex.MatchString(c.Name) {
+			return fmt.Errorf("invalid cursor pagination column %q", c.Name)
+		}
+		if cursorField(c.Name) == models.IDField() {
+			hasID = true
+		}
+	}
+	if !hasID {
+		// the ID makes the order unique, so no row is skipped or repeated.
+		desc := len(order) > 0 && order[len(order)-1].Desc
+		order = append(order, cursorColumn{Name: fmt.Sprintf("%s.%s", models.Alias(), models.IDField()), Desc: desc})
+	}
+
+	slice := reflect.Indirect(reflect.ValueOf(models.Value))
+	if slice.Kind() != reflect.Slice {
+		return fmt.Errorf("cursor pagination expects a pointer to a slice, got %T", models.Value)
+	}
+	elem := slice.Type().Elem()
+	structType := elem
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	token := cursorToken{}
+	var args []interface{}
+	if p.Cursor != "" {
+		var err error
+		token, args, err = decodeCursor(p.Cursor, order, structType)
+		if err != nil {
+			return err
+		}
+	}
+
+	pq := Q(q.Connection)
+	q.Clone(pq)
+	pq.Paginator = nil
+	pq.CursorPaginator = nil
+	if len(args) > 0 {
+		pq.Where(cursorPredicate(order, token.Prev), args...)
+	}
+	for _, c := range order {
+		// pages before the cursor are selected in reverse order.
+		if c.Desc != token.Prev {
+			pq.Order(c.Name + " DESC")
+		} else {
+			pq.Order(c.Name + " ASC")
+		}
+	}
+	pq.Limit(p.PerPage + 1)
+
+	if err := q.Connection.Dialect.SelectMany(q.Connection, models, *pq); err != nil {
+		return q.Connection.translateError(err)
+	}
+
+	hasMore := slice.Len() > p.PerPage
+	if hasMore {
+		slice.Set(slice.Slice(0, p.PerPage))
+	}
+	if token.Prev {
+		swap := reflect.Swapper(slice.Interface())
+		for i, j := 0, slice.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	p.order = order
+	p.CurrentEntriesSize = slice.Len()
+	p.NextCursor, p.PrevCursor = "", ""
+	if slice.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	if (!token.Prev && hasMore) || (token.Prev && p.Cursor != "") {
+		if p.NextCursor, err = encodeCursor(slice.Index(slice.Len()-1), order, false); err != nil {
+			return err
+		}
+	}
+	if (!token.Prev && p.Cursor != "") || (token.Prev && hasMore) {
+		if p.PrevCursor, err = encodeCursor(slice.Index(0), order, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cursorPredicate returns the WHERE clause selecting the rows after the
+// cursor, or before it for cursors pointing backwards. Row values are
+// compared at once if all columns are ordered in the same direction.
+func cursorPredicate(order []cursorColumn, prev bool) string {
+	op := func(c cursorColumn) string {
+		if c.Desc != prev {
+			return "<"
+		}
+		return ">"
+	}
+
+	sameDirection := true
+	names := make([]string, len(order))
+	for i, c := range order {
+		names[i] = c.Name
+		if c.Desc != order[0].Desc {
+			sameDirection = false
+		}
+	}
+
+	if len(order) == 1 {
+		return fmt.Sprintf("%s %s ?", names[0], op(order[0]))
+	}
+	if sameDirection {
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(names, ", "), op(order[0]), strings.TrimSuffix(strings.Repeat("?, ", len(order)), ", "))
+	}
+
+	// (a > ?) OR (a = ? AND b < ?) OR ...
+	ors := make([]string, len(order))
+	for i, c := range order {
+		ands := []string{}
+		for _, prefix := range order[:i] {
+			ands = append(ands, fmt.Sprintf("%s = ?", prefix.Name))
+		}
+		ands = append(ands, fmt.Sprintf("%s %s ?", c.Name, op(c)))
+		ors[i] = fmt.Sprintf("(%s)", strings.Join(ands, " AND "))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(ors, " OR "))
+}
+
+// cursorArgs returns the arguments of the predicate built by cursorPredicate.
+func cursorArgs(order []cursorColumn, values []interface{}) []interface{} {
+	sameDirection := true
+	for _, c := range order {
+		if c.Desc != order[0].Desc {
+			sameDirection = false
+		}
+	}
+	if sameDirection {
+		return values
+	}
+
+	args := []interface{}{}
+	for i := range order {
+		args = append(args, values[:i+1]...)
+	}
+	return args
+}
+
+// cursorField returns the column name of a possibly qualified order column.
+func cursorField(name string) string {
+	name = name[strings.LastIndex(name, ".")+1:]
+	return strings.Trim(name, `"`)
+}
+
+var cursorMapper = reflectx.NewMapperFunc("db", sqlx.NameMapper)
+
+// encodeCursor returns the cursor of the given row.
+func encodeCursor(row reflect.Value, order []cursorColumn, prev bool) (string, error) {
+	row = reflect.Indirect(row)
+	token := cursorToken{Prev: prev}
+	for _, c := range order {
+		fi := cursorMapper.TypeMap(row.Type()).GetByPath(cursorField(c.Name))
+		if fi == nil {
+			return "", fmt.Errorf("cursor pagination column %s is not a field of %s", c.Name, row.Type())
+		}
+		b, err := json.Marshal(reflectx.FieldByIndexesReadOnly(row, fi.Index).Interface())
+		if err != nil {
+			return "", err
+		}
+		token.Values = append(token.Values, b)
+	}
+
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor decodes a cursor and returns the arguments of its predicate,
+// typed like the fields of the order columns.
+func decodeCursor(cursor string, order []cursorColumn, t reflect.Type) (cursorToken, []interface{}, error) {
+	token := cursorToken{}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return token, nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if err := json.Unmarshal(b, &token); err != nil {
+		return token, nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if len(token.Values) != len(order) {
+		return token, nil, errors.New("invalid cursor: it does not match the order columns")
+	}
+
+	values := make([]interface{}, len(order))
+	for i, c := range order {
+		fi := cursorMapper.TypeMap(t).GetByPath(cursorField(c.Name))
+		if fi == nil {
+			return token, nil, fmt.Errorf("cursor pagination column %s is not a field of %s", c.Name, t)
+		}
+		v := reflect.New(fi.Field.Type)
+		if err := json.Unmarshal(token.Values[i], v.Interface()); err != nil {
+			return token, nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		values[i] = v.Elem().Interface()
+	}
+	return token, cursorArgs(order, values), nil
+}
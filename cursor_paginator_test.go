@@ -0,0 +1,138 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CursorPredicate(t *testing.T) {
+	r := require.New(t)
+
+	order := []cursorColumn{{Name: "name"}, {Name: "id"}}
+	r.Equal("(name, id) > (?, ?)", cursorPredicate(order, false))
+	r.Equal("(name, id) < (?, ?)", cursorPredicate(order, true))
+
+	order = []cursorColumn{{Name: "name", Desc: true}, {Name: "id"}}
+	r.Equal("((name < ?) OR (name = ? AND id > ?))", cursorPredicate(order, false))
+	r.Equal("((name > ?) OR (name = ? AND id < ?))", cursorPredicate(order, true))
+	r.Equal([]interface{}{"a", "a", 1}, cursorArgs(order, []interface{}{"a", 1}))
+
+	r.Equal("id < ?", cursorPredicate([]cursorColumn{{Name: "id", Desc: true}}, false))
+}
+
+func Test_PaginateByCursor(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		for _, name := range []string{"c", "a", "e", "b", "d"} {
+			r.NoError(tx.Create(&Composer{Name: name}))
+		}
+
+		names := func(composers []Composer) []string {
+			s := []string{}
+			for _, c := range composers {
+				s = append(s, c.Name)
+			}
+			return s
+		}
+
+		composers := []Composer{}
+		q := tx.PaginateByCursor("", 2, "name")
+		r.NoError(q.All(&composers))
+		r.Equal([]string{"a", "b"}, names(composers))
+		r.Empty(q.CursorPaginator.PrevCursor)
+		r.NotEmpty(q.CursorPaginator.NextCursor)
+
+		q = tx.PaginateByCursor(q.CursorPaginator.NextCursor, 2, "name")
+		r.NoError(q.All(&composers))
+		r.Equal([]string{"c", "d"}, names(composers))
+		r.NotEmpty(q.CursorPaginator.PrevCursor)
+
+		q = tx.PaginateByCursor(q.CursorPaginator.NextCursor, 2, "name")
+		r.NoError(q.All(&composers))
+		r.Equal([]string{"e"}, names(composers))
+		r.Empty(q.CursorPaginator.NextCursor)
+		r.Equal(1, q.CursorPaginator.CurrentEntriesSize)
+
+		q = tx.PaginateByCursor(q.CursorPaginator.PrevCursor, 2, "name")
+		r.NoError(q.All(&composers))
+		r.Equal([]string{"c", "d"}, names(composers))
+		r.NotEmpty(q.CursorPaginator.NextCursor)
+
+		q = tx.PaginateByCursor(q.CursorPaginator.PrevCursor, 2, "name")
+		r.NoError(q.All(&composers))
+		r.Equal([]string{"a", "b"}, names(composers))
+		r.Empty(q.CursorPaginator.PrevCursor)
+		r.NotEmpty(q.CursorPaginator.NextCursor)
+
+		q = tx.Where("name <> ?", "e").PaginateByCursor("", 3, "name desc")
+		r.NoError(q.All(&composers))
+		r.Equal([]string{"d", "c", "b"}, names(composers))
+
+		q = tx.Where("name <> ?", "e").PaginateByCursor(q.CursorPaginator.NextCursor, 3, "name desc")
+		r.NoError(q.All(&composers))
+		r.Equal([]string{"a"}, names(composers))
+
+		r.Error(tx.PaginateByCursor("garbage", 2, "name").All(&composers))
+		r.Error(tx.PaginateByCursor("", 2, "name; drop table composers").All(&composers))
+		r.Error(tx.Order("id").PaginateByCursor("", 2, "name").All(&composers))
+	})
+}
+
+func Test_PaginateByCursor_MixedOrder(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		created := []Composer{}
+		for _, name := range []string{"a", "a", "b", "b", "c"} {
+			c := Composer{Name: name}
+			r.NoError(tx.Create(&c))
+			created = append(created, c)
+		}
+
+		ids := func(composers []Composer) []int {
+			s := []int{}
+			for _, c := range composers {
+				s = append(s, c.ID)
+			}
+			return s
+		}
+
+		composers := []Composer{}
+		q := tx.PaginateByCursor("", 2, "name desc", "id asc")
+		r.NoError(q.All(&composers))
+		r.Equal([]int{created[4].ID, created[2].ID}, ids(composers))
+
+		q = tx.PaginateByCursor(q.CursorPaginator.NextCursor, 2, "name desc", "id asc")
+		r.NoError(q.All(&composers))
+		r.Equal([]int{created[3].ID, created[0].ID}, ids(composers))
+
+		q = tx.PaginateByCursor(q.CursorPaginator.NextCursor, 2, "name desc", "id asc")
+		r.NoError(q.All(&composers))
+		r.Equal([]int{created[1].ID}, ids(composers))
+
+		q = tx.PaginateByCursor(q.CursorPaginator.PrevCursor, 2, "name desc", "id asc")
+		r.NoError(q.All(&composers))
+		r.Equal([]int{created[3].ID, created[0].ID}, ids(composers))
+
+		seen := map[int]bool{}
+		cursor := ""
+		for i := 0; i < 3; i++ {
+			q = tx.PaginateByCursor(cursor, 2, "created_at desc")
+			r.NoError(q.All(&composers))
+			for _, c := range composers {
+				seen[c.ID] = true
+			}
+			cursor = q.CursorPaginator.NextCursor
+		}
+		r.Empty(cursor)
+		r.Len(seen, 5)
+	})
+}
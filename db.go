@@ -30,3 +30,22 @@ func (db *dB) Rollback() error {
 func (db *dB) Commit() error {
 	return nil
 }
+
+// storeDB returns the database under the wrappers of a store, unless the
+// store is a transaction or a wrapper it doesn't know.
+func storeDB(s Store) (*dB, bool) {
+	for {
+		switch t := s.(type) {
+		case *dB:
+			return t, true
+		case contextStore:
+			s = t.Store
+		case interceptedStore:
+			s = t.Store
+		case ydbStore:
+			s = t.Store
+		default:
+			return nil, false
+		}
+	}
+}
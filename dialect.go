@@ -17,6 +17,92 @@ type crudable interface {
 	Delete(*Connection, *Model, Query) error
 }
 
+// batchCreatable is implemented by dialects which can insert several models
+// with a single statement. Dialects without it insert models one by one.
+type batchCreatable interface {
+	CreateMany(*Connection, []*Model, columns.Columns) error
+}
+
+// upsertable is implemented by dialects which can insert a model or update
+// the conflicting row with a single statement.
+type upsertable interface {
+	Upsert(*Connection, *Model, columns.Columns, UpsertOptions) error
+}
+
+// savepointer is implemented by dialects which support nested transactions
+// with savepoints.
+type savepointer interface {
+	// SavepointSQL returns the statement of the savepoint operation, which
+	// is one of "SAVEPOINT", "ROLLBACK TO SAVEPOINT" or "RELEASE SAVEPOINT".
+	SavepointSQL(op, name string) (string, error)
+}
+
+// explainable is implemented by dialects which can show the execution plan
+// of a query.
+type explainable interface {
+	Explain(c *Connection, query string, args []interface{}, opts ExplainOptions) (*ExplainPlan, error)
+}
+
+// retryable is implemented by dialects which can tell the errors, such as
+// serialization failures and deadlocks, of the transactions which may
+// succeed when run again.
+type retryable interface {
+	IsRetryable(err error) bool
+}
+
+// errorTranslator is implemented by dialects which map the errors of their
+// driver to the typed errors of pop, such as ErrUniqueViolation.
+type errorTranslator interface {
+	TranslateError(err error) error
+}
+
+// lockable is implemented by dialects which support row-level locking
+// clauses in SELECT statements. LockClause returns the clause appended to
+// the statement, such as "FOR UPDATE SKIP LOCKED".
+type lockable interface {
+	LockClause(RowLock) string
+}
+
+// featureSupporter is implemented by dialects whose support of some SQL
+// features depends on the version of the server, such as MySQL 5.7. Supports
+// reports if the server supports the given feature, one of the Feature
+// constants. Dialects without it are assumed to support all of them.
+type featureSupporter interface {
+	Supports(feature string) bool
+}
+
+// The SQL features whose support is checked with Supports.
+const (
+	// FeatureWith is the WITH clause of common table expressions.
+	FeatureWith = "WITH"
+	// FeatureLockWait is the NOWAIT and SKIP LOCKED policies of row locks.
+	FeatureLockWait = "NOWAIT"
+	// FeatureIntersect is the INTERSECT and EXCEPT set operations.
+	FeatureIntersect = "INTERSECT"
+)
+
+// supports tells if the server of the dialect supports the given feature.
+func supports(d Dialect, feature string) bool {
+	if f, ok := d.(featureSupporter); ok {
+		return f.Supports(feature)
+	}
+	return true
+}
+
+// UpsertOptions describes how an upsert resolves a conflict.
+type UpsertOptions struct {
+	// Conflict lists the columns of the unique constraint to check.
+	Conflict []string
+	// Update lists the columns to overwrite on conflict. The insert is
+	// skipped on conflict when it is empty.
+	Update []string
+	// Version is the optimistic locking column, incremented on conflict.
+	Version string
+	// Read lists the columns whose stored values are read back into the
+	// model, such as the `created_at` of an existing row.
+	Read []string
+}
+
 type fizzable interface {
 	FizzTranslator() fizz.Translator
 }
@@ -25,7 +111,15 @@ type quotable interface {
 	Quote(key string) string
 }
 
-type dialect interface {
+// Dialect is the interface a database dialect must implement to be used
+// by a `Connection`. Dialects that are not shipped with pop can be plugged
+// in with `RegisterDialect`.
+//
+// A dialect can implement the optional methods of the built-in ones to
+// support more features, such as `CreateMany`, `Upsert`, `SavepointSQL`,
+// `Explain`, `IsRetryable`, `TranslateError`, `LockClause(RowLock) string`,
+// `Supports`, `AfterOpen`, `WrapScalar` and `WrapStore(Store) Store`.
+type Dialect interface {
 	crudable
 	fizzable
 	quotable
@@ -46,3 +140,17 @@ type dialect interface {
 type afterOpenable interface {
 	AfterOpen(*Connection) error
 }
+
+// scalarWrapper is implemented by dialects whose driver returns the results
+// of expressions, such as MIN(created_at), with another type than the one of
+// the column. The wrapper adapts the destination of an aggregate.
+type scalarWrapper interface {
+	WrapScalar(dest interface{}) interface{}
+}
+
+// storeWrapper is implemented by dialects which need to adapt statements or
+// arguments before they reach the driver. WrapStore is applied to the
+// store of a connection and of each of its transactions.
+type storeWrapper interface {
+	WrapStore(Store) Store
+}
@@ -3,6 +3,7 @@ package pop
 import (
 	"bytes"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -37,7 +38,7 @@ func init() {
 	newConnection[nameCockroach] = newCockroach
 }
 
-var _ dialect = &cockroach{}
+var _ Dialect = &cockroach{}
 
 // ServerInfo holds informational data about connected database server.
 type cockroachInfo struct {
@@ -83,7 +84,7 @@ func (p *cockroach) Create(c *Connection, model *Model, cols columns.Columns) er
 		} else {
 			query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING %s", p.Quote(model.TableName()), model.IDField())
 		}
-		txlog(logging.SQL, c, query, model.Value)
+		c.log(logging.SQL, query, model.Value)
 		rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
 		if err != nil {
 			return fmt.Errorf("named insert: %w", err)
@@ -120,7 +121,7 @@ func (p *cockroach) Create(c *Connection, model *Model, cols columns.Columns) er
 			w.Add(model.IDField())
 			query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s", p.Quote(model.TableName()), w.QuotedString(p), w.SymbolizedString(), model.IDField())
 		}
-		txlog(logging.SQL, c, query, model.Value)
+		c.log(logging.SQL, query, model.Value)
 		rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
 		if err != nil {
 			return fmt.Errorf("named insert: %w", err)
@@ -142,33 +143,70 @@ func (p *cockroach) Create(c *Connection, model *Model, cols columns.Columns) er
 		model.setID(id)
 		return nil
 	}
-	return genericCreate(c, model, cols, p)
+	return GenericCreate(c, model, cols, p)
+}
+
+func (p *cockroach) CreateMany(c *Connection, models []*Model, cols columns.Columns) error {
+	return GenericCreateManyReturning(c, models, cols, p)
+}
+
+func (p *cockroach) Upsert(c *Connection, model *Model, cols columns.Columns, opts UpsertOptions) error {
+	if err := GenericUpsertReturning(c, model, cols, opts, p); err != nil {
+		return fmt.Errorf("cockroach upsert: %w", err)
+	}
+	return nil
 }
 
 func (p *cockroach) Update(c *Connection, model *Model, cols columns.Columns) error {
-	return genericUpdate(c, model, cols, p)
+	return GenericUpdate(c, model, cols, p)
 }
 
 func (p *cockroach) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
-	return genericUpdateQuery(c, model, cols, p, query, sqlx.DOLLAR)
+	return GenericUpdateQuery(c, model, cols, p, query, sqlx.DOLLAR)
+}
+
+func (p *cockroach) LockClause(l RowLock) string {
+	return genericLockClause(l)
+}
+
+// Explain returns the plan in the text format. CockroachDB has no JSON
+// format for plans, so ExplainJSON is not supported and returns an error,
+// while the default format is text.
+func (p *cockroach) Explain(c *Connection, query string, args []interface{}, opts ExplainOptions) (*ExplainPlan, error) {
+	if opts.Format == ExplainJSON {
+		return nil, errors.New("EXPLAIN in JSON is not supported by CockroachDB")
+	}
+	stmt := "EXPLAIN " + query
+	if opts.Analyze {
+		stmt = "EXPLAIN ANALYZE " + query
+	}
+	return explainText(c, stmt, args...)
+}
+
+func (p *cockroach) IsRetryable(err error) bool {
+	return isRetryablePgError(err)
+}
+
+func (p *cockroach) TranslateError(err error) error {
+	return translatePgError(err)
 }
 
 func (p *cockroach) Destroy(c *Connection, model *Model) error {
 	stmt := p.TranslateSQL(fmt.Sprintf("DELETE FROM %s AS %s WHERE %s", p.Quote(model.TableName()), model.Alias(), model.WhereID()))
-	_, err := genericExec(c, stmt, model.ID())
+	_, err := GenericExec(c, stmt, model.ID())
 	return err
 }
 
 func (p *cockroach) Delete(c *Connection, model *Model, query Query) error {
-	return genericDelete(c, model, query)
+	return GenericDelete(c, model, query)
 }
 
 func (p *cockroach) SelectOne(c *Connection, model *Model, query Query) error {
-	return genericSelectOne(c, model, query)
+	return GenericSelectOne(c, model, query)
 }
 
 func (p *cockroach) SelectMany(c *Connection, models *Model, query Query) error {
-	return genericSelectMany(c, models, query)
+	return GenericSelectMany(c, models, query)
 }
 
 func (p *cockroach) CreateDB() error {
@@ -288,7 +326,7 @@ func cockroachDumpSchema(deets *ConnectionDetails, cmd *exec.Cmd, w io.Writer) e
 }
 
 func (p *cockroach) LoadSchema(r io.Reader) error {
-	return genericLoadSchema(p, r)
+	return GenericLoadSchema(p, r)
 }
 
 func (p *cockroach) TruncateAll(tx *Connection) error {
@@ -337,7 +375,7 @@ func (p *cockroach) AfterOpen(c *Connection) error {
 	return nil
 }
 
-func newCockroach(deets *ConnectionDetails) (dialect, error) {
+func newCockroach(deets *ConnectionDetails) (Dialect, error) {
 	deets.Dialect = "postgres"
 	d := &cockroach{
 		commonDialect:  commonDialect{ConnectionDetails: deets},
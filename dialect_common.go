@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/gobuffalo/pop/v6/columns"
 	"github.com/gobuffalo/pop/v6/logging"
@@ -29,6 +32,12 @@ func (commonDialect) Lock(fn func() error) error {
 	return fn()
 }
 
+// SavepointSQL returns the standard SQL savepoint statements, which are
+// understood by all built-in dialects except YDB.
+func (commonDialect) SavepointSQL(op, name string) (string, error) {
+	return fmt.Sprintf("%s %s", op, name), nil
+}
+
 func (commonDialect) Quote(key string) string {
 	parts := strings.Split(key, ".")
 
@@ -42,7 +51,10 @@ func (commonDialect) Quote(key string) string {
 	return strings.Join(parts, ".")
 }
 
-func genericCreate(c *Connection, model *Model, cols columns.Columns, quoter quotable) error {
+// GenericCreate inserts the model into its table and sets the ID of the
+// model for integer keys using `LastInsertId`. UUID keys are generated when
+// missing. Dialects without a specific INSERT syntax can delegate to it.
+func GenericCreate(c *Connection, model *Model, cols columns.Columns, quoter quotable) error {
 	keyType, err := model.PrimaryKeyType()
 	if err != nil {
 		return err
@@ -53,7 +65,7 @@ func genericCreate(c *Connection, model *Model, cols columns.Columns, quoter quo
 		cols.Remove(model.IDField())
 		w := cols.Writeable()
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoter.Quote(model.TableName()), w.QuotedString(quoter), w.SymbolizedString())
-		txlog(logging.SQL, c, query, model.Value)
+		c.log(logging.SQL, query, model.Value)
 		res, err := c.Store.NamedExecContext(model.ctx, query, model.Value)
 		if err != nil {
 			return err
@@ -81,7 +93,7 @@ func genericCreate(c *Connection, model *Model, cols columns.Columns, quoter quo
 		w := cols.Writeable()
 		w.Add(model.IDField())
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoter.Quote(model.TableName()), w.QuotedString(quoter), w.SymbolizedString())
-		txlog(logging.SQL, c, query, model.Value)
+		c.log(logging.SQL, query, model.Value)
 		if _, err := c.Store.NamedExecContext(model.ctx, query, model.Value); err != nil {
 			return fmt.Errorf("named insert: %w", err)
 		}
@@ -90,17 +102,330 @@ func genericCreate(c *Connection, model *Model, cols columns.Columns, quoter quo
 	return fmt.Errorf("can not use %s as a primary key type!", keyType)
 }
 
-func genericUpdate(c *Connection, model *Model, cols columns.Columns, quoter quotable) error {
-	stmt := fmt.Sprintf("UPDATE %s AS %s SET %s WHERE %s", quoter.Quote(model.TableName()), model.Alias(), cols.Writeable().QuotedUpdateString(quoter), model.WhereNamedID())
-	txlog(logging.SQL, c, stmt, model.ID())
-	_, err := c.Store.NamedExecContext(model.ctx, stmt, model.Value)
+// GenericCreateMany inserts the models with a single multi-row INSERT. The
+// IDs of integer keys are set from the consecutive range starting at
+// `LastInsertId`, which is what MySQL reports for multi-row inserts, unless
+// the caller set all of them.
+func GenericCreateMany(c *Connection, models []*Model, cols columns.Columns, quoter quotable) error {
+	return genericCreateMany(c, models, cols, quoter, false, 1)
+}
+
+// genericCreateMany inserts the models with a single multi-row INSERT. The
+// IDs of integer keys are set from the range starting or, if lastID is true,
+// ending at `LastInsertId`, with step as the difference between two IDs. The
+// range is only consecutive if no other insert can run concurrently, such as
+// under the write lock of SQLite.
+func genericCreateMany(c *Connection, models []*Model, cols columns.Columns, quoter quotable, lastID bool, step int64) error {
+	if len(models) == 0 {
+		return nil
+	}
+	model := models[0]
+	keyType, err := model.PrimaryKeyType()
+	if err != nil {
+		return err
+	}
+	switch keyType {
+	case "int", "int64":
+		preset, err := batchPresetIDs(models)
+		if err != nil {
+			return err
+		}
+		if preset {
+			return insertBatchWithIDs(c, models, cols, quoter)
+		}
+		cols.Remove(model.IDField())
+		w := cols.Writeable()
+		if len(w.Cols) == 0 {
+			return errors.New("batch insert requires at least one column besides the ID")
+		}
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoter.Quote(model.TableName()), w.QuotedString(quoter), w.SymbolizedString())
+		c.log(logging.SQL, query)
+		res, err := c.Store.NamedExecContext(model.ctx, query, batchValues(models))
+		if err != nil {
+			return fmt.Errorf("named batch insert: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		if lastID {
+			id -= int64(len(models)-1) * step
+		}
+		for i, m := range models {
+			m.setID(id + int64(i)*step)
+		}
+		return nil
+	case "UUID", "string":
+		if err := setBatchIDs(models, keyType); err != nil {
+			return err
+		}
+		return insertBatchWithIDs(c, models, cols, quoter)
+	}
+	return fmt.Errorf("can not use %s as a primary key type!", keyType)
+}
+
+// insertBatchWithIDs inserts the models with the IDs they already have.
+func insertBatchWithIDs(c *Connection, models []*Model, cols columns.Columns, quoter quotable) error {
+	model := models[0]
+	w := cols.Writeable()
+	w.Add(model.IDField())
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoter.Quote(model.TableName()), w.QuotedString(quoter), w.SymbolizedString())
+	c.log(logging.SQL, query)
+	if _, err := c.Store.NamedExecContext(model.ctx, query, batchValues(models)); err != nil {
+		return fmt.Errorf("named batch insert: %w", err)
+	}
+	return nil
+}
+
+// GenericCreateManyReturning inserts the models with a single multi-row
+// INSERT and reads integer IDs back with a RETURNING clause. The IDs are
+// assigned to the models in the order of the returned rows, which PostgreSQL
+// and CockroachDB return in the order of the VALUES list for a plain INSERT,
+// although SQL doesn't guarantee it. Don't use it on databases which don't,
+// such as SQLite. IDs set by the caller on all the models are inserted as
+// they are.
+func GenericCreateManyReturning(c *Connection, models []*Model, cols columns.Columns, quoter quotable) error {
+	if len(models) == 0 {
+		return nil
+	}
+	model := models[0]
+	keyType, err := model.PrimaryKeyType()
+	if err != nil {
+		return err
+	}
+	if keyType != "int" && keyType != "int64" {
+		return GenericCreateMany(c, models, cols, quoter)
+	}
+	preset, err := batchPresetIDs(models)
+	if err != nil {
+		return err
+	}
+	if preset {
+		return insertBatchWithIDs(c, models, cols, quoter)
+	}
+
+	cols.Remove(model.IDField())
+	w := cols.Writeable()
+	if len(w.Cols) == 0 {
+		return errors.New("batch insert requires at least one column besides the ID")
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s", quoter.Quote(model.TableName()), w.QuotedString(quoter), w.SymbolizedString(), model.IDField())
+	c.log(logging.SQL, query)
+	rows, err := c.Store.NamedQueryContext(model.ctx, query, batchValues(models))
+	if err != nil {
+		return fmt.Errorf("named batch insert: %w", err)
+	}
+	defer rows.Close()
+	for _, m := range models {
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("named batch insert: next: %w", err)
+			}
+			return fmt.Errorf("named batch insert: %w", sql.ErrNoRows)
+		}
+		var id interface{}
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("named batch insert: scan: %w", err)
+		}
+		m.setID(id)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("named batch insert: close: %w", err)
+	}
+	return nil
+}
+
+// upsertColumns returns the columns to insert with an upsert. Contrary to
+// Create, a set integer ID is kept, so rows can be upserted by their ID.
+func upsertColumns(model *Model, cols columns.Columns) (*columns.WriteableColumns, error) {
+	keyType, err := model.PrimaryKeyType()
+	if err != nil {
+		return nil, err
+	}
+	w := cols.Writeable()
+	switch keyType {
+	case "UUID", "string":
+		if err := setBatchIDs([]*Model{model}, keyType); err != nil {
+			return nil, err
+		}
+		w.Add(model.IDField())
+	default:
+		if IsZeroOfUnderlyingType(model.ID()) {
+			w.Remove(model.IDField())
+		} else {
+			w.Add(model.IDField())
+		}
+	}
+	return w, nil
+}
+
+// GenericUpsertReturning upserts the model with `ON CONFLICT` and reads the
+// ID and the Read columns of the inserted or updated row back with a
+// RETURNING clause. The model is left untouched when the insert is skipped.
+func GenericUpsertReturning(c *Connection, model *Model, cols columns.Columns, opts UpsertOptions, quoter quotable) error {
+	w, err := upsertColumns(model, cols)
+	if err != nil {
+		return err
+	}
+
+	conflict := make([]string, len(opts.Conflict))
+	for i, col := range opts.Conflict {
+		conflict[i] = quoter.Quote(col)
+	}
+	onConflict := "ON CONFLICT"
+	if len(conflict) > 0 {
+		onConflict = fmt.Sprintf("ON CONFLICT (%s)", strings.Join(conflict, ", "))
+	}
+	if len(opts.Update) == 0 {
+		onConflict += " DO NOTHING"
+	} else {
+		set := make([]string, 0, len(opts.Update)+1)
+		for _, col := range opts.Update {
+			set = append(set, fmt.Sprintf("%s = EXCLUDED.%s", quoter.Quote(col), quoter.Quote(col)))
+		}
+		if opts.Version != "" {
+			set = append(set, fmt.Sprintf("%s = %s.%s + 1", quoter.Quote(opts.Version), quoter.Quote(model.TableName()), quoter.Quote(opts.Version)))
+		}
+		onConflict += " DO UPDATE SET " + strings.Join(set, ", ")
+	}
+
+	returning := []string{quoter.Quote(model.IDField())}
+	for _, col := range opts.Read {
+		returning = append(returning, quoter.Quote(col))
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) %s RETURNING %s", quoter.Quote(model.TableName()), w.QuotedString(quoter), w.SymbolizedString(), onConflict, strings.Join(returning, ", "))
+	c.log(logging.SQL, query, model.Value)
+	rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
+	if err != nil {
+		return fmt.Errorf("named upsert: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return rows.Err()
+	}
+	if err := rows.StructScan(model.Value); err != nil {
+		return fmt.Errorf("named upsert: scan: %w", err)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("named upsert: close: %w", err)
+	}
+	return nil
+}
+
+// batchValues returns the values of the models as a slice sqlx can bind to
+// a multi-row INSERT.
+func batchValues(models []*Model) []interface{} {
+	values := make([]interface{}, len(models))
+	for i, m := range models {
+		values[i] = m.Value
+	}
+	return values
+}
+
+// batchPresetIDs tells if the caller set the integer keys of all the models.
+// A batch where only some of them are set is rejected, since a single INSERT
+// can not both write and generate the IDs.
+func batchPresetIDs(models []*Model) (bool, error) {
+	preset := 0
+	for _, m := range models {
+		if !reflect.ValueOf(m.ID()).IsZero() {
+			preset++
+		}
+	}
+	switch preset {
+	case 0:
+		return false, nil
+	case len(models):
+		return true, nil
+	}
+	return false, errors.New("batch insert can not mix preset and generated integer IDs")
+}
+
+// setBatchIDs generates missing UUID keys and ensures string keys are set.
+func setBatchIDs(models []*Model, keyType string) error {
+	for _, m := range models {
+		if keyType == "UUID" {
+			if m.ID() == emptyUUID {
+				u, err := uuid.NewV4()
+				if err != nil {
+					return err
+				}
+				m.setID(u)
+			}
+		} else if m.ID() == "" {
+			return fmt.Errorf("missing ID value")
+		}
+	}
+	return nil
+}
+
+// genericLockClause returns the row-level locking clause of PostgreSQL and
+// MySQL 8.
+func genericLockClause(l RowLock) string {
+	if l.Wait == "" {
+		return "FOR " + l.Strength
+	}
+	return fmt.Sprintf("FOR %s %s", l.Strength, l.Wait)
+}
+
+// GenericUpdate updates the given columns of the row matching the model ID.
+// If the optimistic locking column of the model is among the columns, the
+// update also checks and increments it.
+func GenericUpdate(c *Connection, model *Model, cols columns.Columns, quoter quotable) error {
+	set, version := versionedUpdateString(model, cols, quoter)
+	where := model.WhereNamedID()
+	if version != "" {
+		where = fmt.Sprintf("%s AND %s.%s = :%s", where, model.Alias(), quoter.Quote(version), version)
+	}
+	stmt := fmt.Sprintf("UPDATE %s AS %s SET %s WHERE %s", quoter.Quote(model.TableName()), model.Alias(), set, where)
+	c.log(logging.SQL, stmt, model.ID())
+	res, err := c.Store.NamedExecContext(model.ctx, stmt, model.Value)
 	if err != nil {
 		return err
 	}
+	if version != "" {
+		return checkVersion(model, res)
+	}
 	return nil
 }
 
-func genericUpdateQuery(c *Connection, model *Model, cols columns.Columns, quoter quotable, query Query, bindType int) (int64, error) {
+// versionedUpdateString returns the SET part of an UPDATE of the writeable
+// columns. If the optimistic locking column of the model is among them, it is
+// incremented by the database and its name is returned.
+func versionedUpdateString(model *Model, cols columns.Columns, quoter quotable) (set string, version string) {
+	w := cols.Writeable()
+	version = model.versionColumn()
+	if _, ok := w.Cols[version]; version == "" || !ok {
+		return w.QuotedUpdateString(quoter), ""
+	}
+
+	w.Remove(version)
+	sets := []string{}
+	if s := w.QuotedUpdateString(quoter); s != "" {
+		sets = append(sets, s)
+	}
+	sets = append(sets, fmt.Sprintf("%s = %s + 1", quoter.Quote(version), quoter.Quote(version)))
+	return strings.Join(sets, ", "), version
+}
+
+// checkVersion increments the optimistic locking version of the model after
+// a versioned update, or returns ErrStaleObject if no row matched the version
+// of the model.
+func checkVersion(model *Model, res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrStaleObject
+	}
+	return model.incrementVersion()
+}
+
+// GenericUpdateQuery updates the given columns of all rows matched by the
+// query and returns the number of affected rows. bindType is one of the
+// sqlx bindvar types used to rebind the resulting statement.
+func GenericUpdateQuery(c *Connection, model *Model, cols columns.Columns, quoter quotable, query Query, bindType int) (int64, error) {
 	q := fmt.Sprintf("UPDATE %s AS %s SET %s", quoter.Quote(model.TableName()), model.Alias(), cols.Writeable().QuotedUpdateString(quoter))
 
 	q, updateArgs, err := sqlx.Named(q, model.Value)
@@ -113,7 +438,7 @@ func genericUpdateQuery(c *Connection, model *Model, cols columns.Columns, quote
 
 	q = sqlx.Rebind(bindType, q)
 
-	result, err := genericExec(c, q, append(updateArgs, sb.args...)...)
+	result, err := GenericExec(c, q, append(updateArgs, sb.args...)...)
 	if err != nil {
 		return 0, err
 	}
@@ -126,48 +451,59 @@ func genericUpdateQuery(c *Connection, model *Model, cols columns.Columns, quote
 	return n, err
 }
 
-func genericDestroy(c *Connection, model *Model, quoter quotable) error {
+// GenericDestroy deletes the row matching the model ID.
+func GenericDestroy(c *Connection, model *Model, quoter quotable) error {
 	stmt := fmt.Sprintf("DELETE FROM %s AS %s WHERE %s", quoter.Quote(model.TableName()), model.Alias(), model.WhereID())
-	_, err := genericExec(c, stmt, model.ID())
+	_, err := GenericExec(c, stmt, model.ID())
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func genericDelete(c *Connection, model *Model, query Query) error {
+// GenericDelete deletes all rows matched by the query.
+func GenericDelete(c *Connection, model *Model, query Query) error {
 	sqlQuery, args := query.ToSQL(model)
-	_, err := genericExec(c, sqlQuery, args...)
+	_, err := GenericExec(c, sqlQuery, args...)
 	return err
 }
 
-func genericExec(c *Connection, stmt string, args ...interface{}) (sql.Result, error) {
-	txlog(logging.SQL, c, stmt, args...)
+// GenericExec logs and executes the given statement on the connection.
+func GenericExec(c *Connection, stmt string, args ...interface{}) (sql.Result, error) {
+	c.log(logging.SQL, stmt, args...)
 	res, err := c.Store.ExecContext(c.Context(), stmt, args...)
 	return res, err
 }
 
-func genericSelectOne(c *Connection, model *Model, query Query) error {
+// GenericSelectOne scans the first row matched by the query into the model.
+func GenericSelectOne(c *Connection, model *Model, query Query) error {
 	sqlQuery, args := query.ToSQL(model)
-	txlog(logging.SQL, query.Connection, sqlQuery, args...)
-	err := c.Store.GetContext(model.ctx, model.Value, sqlQuery, args...)
+	query.Connection.log(logging.SQL, sqlQuery, args...)
+	start := time.Now()
+	err := c.readStore().GetContext(model.ctx, model.Value, sqlQuery, args...)
 	if err != nil {
 		return err
 	}
+	explainSlowQuery(c, time.Since(start), sqlQuery, args...)
 	return nil
 }
 
-func genericSelectMany(c *Connection, models *Model, query Query) error {
+// GenericSelectMany scans all rows matched by the query into the models.
+func GenericSelectMany(c *Connection, models *Model, query Query) error {
 	sqlQuery, args := query.ToSQL(models)
-	txlog(logging.SQL, query.Connection, sqlQuery, args...)
-	err := c.Store.SelectContext(models.ctx, models.Value, sqlQuery, args...)
+	query.Connection.log(logging.SQL, sqlQuery, args...)
+	start := time.Now()
+	err := c.readStore().SelectContext(models.ctx, models.Value, sqlQuery, args...)
 	if err != nil {
 		return err
 	}
+	explainSlowQuery(c, time.Since(start), sqlQuery, args...)
 	return nil
 }
 
-func genericLoadSchema(d dialect, r io.Reader) error {
+// GenericLoadSchema executes the schema read from r against the database
+// of the given dialect.
+func GenericLoadSchema(d Dialect, r io.Reader) error {
 	deets := d.Details()
 
 	// Open DB connection on the target DB
@@ -197,7 +533,8 @@ func genericLoadSchema(d dialect, r io.Reader) error {
 	return nil
 }
 
-func genericDumpSchema(deets *ConnectionDetails, cmd *exec.Cmd, w io.Writer) error {
+// GenericDumpSchema runs the given dump command and writes its output to w.
+func GenericDumpSchema(deets *ConnectionDetails, cmd *exec.Cmd, w io.Writer) error {
 	log(logging.SQL, strings.Join(cmd.Args, " "))
 
 	bb := &bytes.Buffer{}
@@ -14,7 +14,7 @@ func init() {
 	newConnection[nameMariaDB] = newMySQL
 }
 
-var _ dialect = &mariaDB{}
+var _ Dialect = &mariaDB{}
 
 type mariaDB struct {
 	mysql
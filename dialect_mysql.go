@@ -2,10 +2,12 @@ package pop
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 
 	_mysql "github.com/go-sql-driver/mysql" // Load MySQL Go driver
@@ -28,10 +30,16 @@ func init() {
 	newConnection[nameMySQL] = newMySQL
 }
 
-var _ dialect = &mysql{}
+var _ Dialect = &mysql{}
 
 type mysql struct {
 	commonDialect
+	// version is the version of the server, read when the connection is
+	// opened.
+	version string
+	// increment is the auto_increment_increment of the server, the step
+	// between the IDs generated by a multi-row insert.
+	increment int64
 }
 
 func (m *mysql) Name() string {
@@ -83,30 +91,264 @@ func (m *mysql) MigrationURL() string {
 }
 
 func (m *mysql) Create(c *Connection, model *Model, cols columns.Columns) error {
-	if err := genericCreate(c, model, cols, m); err != nil {
+	if err := GenericCreate(c, model, cols, m); err != nil {
 		return fmt.Errorf("mysql create: %w", err)
 	}
 	return nil
 }
 
+// CreateMany derives the IDs of a multi-row insert from LastInsertId, which
+// is the first of them, and the auto_increment_increment read when the
+// connection was opened. Setting another increment on a session is not
+// supported.
+func (m *mysql) CreateMany(c *Connection, models []*Model, cols columns.Columns) error {
+	step := m.increment
+	if step < 1 {
+		step = 1
+	}
+	if err := genericCreateMany(c, models, cols, m, false, step); err != nil {
+		return fmt.Errorf("mysql create many: %w", err)
+	}
+	return nil
+}
+
+// Upsert uses `ON DUPLICATE KEY UPDATE`, or `INSERT IGNORE` when no column
+// is updated. MySQL checks every unique key of the table, so the conflict
+// columns are not part of the statement. The ID and Read columns of an
+// updated row are only read back for integer keys, the latter with a SELECT.
+func (m *mysql) Upsert(c *Connection, model *Model, cols columns.Columns, opts UpsertOptions) error {
+	w, err := upsertColumns(model, cols)
+	if err != nil {
+		return fmt.Errorf("mysql upsert: %w", err)
+	}
+
+	insert := "INSERT"
+	onDuplicate := ""
+	if len(opts.Update) == 0 {
+		insert = "INSERT IGNORE"
+	} else {
+		set := make([]string, 0, len(opts.Update)+1)
+		for _, col := range opts.Update {
+			set = append(set, fmt.Sprintf("%s = VALUES(%s)", m.Quote(col), m.Quote(col)))
+		}
+		if opts.Version != "" {
+			set = append(set, fmt.Sprintf("%s = %s + 1", m.Quote(opts.Version), m.Quote(opts.Version)))
+		}
+		// makes LastInsertId report the ID of the updated row.
+		id := m.Quote(model.IDField())
+		set = append(set, fmt.Sprintf("%s = LAST_INSERT_ID(%s)", id, id))
+		onDuplicate = " ON DUPLICATE KEY UPDATE " + strings.Join(set, ", ")
+	}
+
+	query := fmt.Sprintf("%s INTO %s (%s) VALUES (%s)%s", insert, m.Quote(model.TableName()), w.QuotedString(m), w.SymbolizedString(), onDuplicate)
+	c.log(logging.SQL, query, model.Value)
+	res, err := c.Store.NamedExecContext(model.ctx, query, model.Value)
+	if err != nil {
+		return fmt.Errorf("mysql upsert: %w", err)
+	}
+
+	keyType, err := model.PrimaryKeyType()
+	if err != nil {
+		return err
+	}
+	if keyType != "int" && keyType != "int64" {
+		return nil
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("mysql upsert: %w", err)
+	}
+	if id == 0 {
+		return nil
+	}
+	model.setID(id)
+	if len(opts.Read) == 0 {
+		return nil
+	}
+
+	read := make([]string, len(opts.Read))
+	for i, col := range opts.Read {
+		read[i] = m.Quote(col)
+	}
+	query = fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", strings.Join(read, ", "), m.Quote(model.TableName()), m.Quote(model.IDField()))
+	c.log(logging.SQL, query, id)
+	if err := c.Store.GetContext(model.ctx, model.Value, query, id); err != nil {
+		return fmt.Errorf("mysql upsert: %w", err)
+	}
+	return nil
+}
+
 func (m *mysql) Update(c *Connection, model *Model, cols columns.Columns) error {
-	if err := genericUpdate(c, model, cols, m); err != nil {
+	if err := GenericUpdate(c, model, cols, m); err != nil {
 		return fmt.Errorf("mysql update: %w", err)
 	}
 	return nil
 }
 
 func (m *mysql) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
-	if n, err := genericUpdateQuery(c, model, cols, m, query, sqlx.QUESTION); err != nil {
+	if n, err := GenericUpdateQuery(c, model, cols, m, query, sqlx.QUESTION); err != nil {
 		return n, fmt.Errorf("mysql update query: %w", err)
 	} else {
 		return n, nil
 	}
 }
 
+// LockClause uses `LOCK IN SHARE MODE` for shared locks on MariaDB and
+// MySQL 5.7, which do not support `FOR SHARE`.
+func (m *mysql) LockClause(l RowLock) string {
+	if l.Strength == LockShare && (CanonicalDialect(m.Details().Dialect) == nameMariaDB || m.before(8, 0, 0)) {
+		return strings.TrimSpace("LOCK IN SHARE MODE " + l.Wait)
+	}
+	return genericLockClause(l)
+}
+
+// AfterOpen reads the version of the server, as the syntax supported by
+// MySQL 5.7 differs from the one of MySQL 8, and its auto_increment_increment.
+func (m *mysql) AfterOpen(c *Connection) error {
+	server := struct {
+		Version   string `db:"version"`
+		Increment int64  `db:"increment"`
+	}{}
+	if err := c.Store.GetContext(c.Context(), &server, "SELECT VERSION() AS version, @@auto_increment_increment AS increment"); err != nil {
+		return err
+	}
+	m.version, m.increment = server.Version, server.Increment
+	log(logging.Debug, "server: mysql %v", m.version)
+	return nil
+}
+
+// Supports reports WITH, NOWAIT and SKIP LOCKED as unsupported before MySQL
+// 8.0, and INTERSECT and EXCEPT before MySQL 8.0.31.
+func (m *mysql) Supports(feature string) bool {
+	switch feature {
+	case FeatureWith, FeatureLockWait:
+		return !m.before(8, 0, 0)
+	case FeatureIntersect:
+		return !m.before(8, 0, 31)
+	}
+	return true
+}
+
+// before tells if the server is MySQL older than the given version. MariaDB,
+// and servers whose version was not read, are reported as recent.
+func (m *mysql) before(major, minor, patch int) bool {
+	if m.version == "" || strings.Contains(strings.ToLower(m.version), "mariadb") {
+		return false
+	}
+	v := strings.SplitN(m.version, "-", 2)[0]
+	want := []int{major, minor, patch}
+	for i, s := range strings.SplitN(v, ".", 3) {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return false
+		}
+		if n != want[i] {
+			return n < want[i]
+		}
+	}
+	return false
+}
+
+// Explain returns the plan of the query. EXPLAIN ANALYZE of MySQL only
+// supports the tree format, from MySQL 8.0.18, while MariaDB analyzes with
+// ANALYZE. The text plan of MySQL before 8.0.16, which has no tree format, is
+// the table of a plain EXPLAIN.
+func (m *mysql) Explain(c *Connection, query string, args []interface{}, opts ExplainOptions) (*ExplainPlan, error) {
+	mariadb := CanonicalDialect(m.Details().Dialect) == nameMariaDB
+	text := opts.Format == ExplainText
+
+	var stmt string
+	switch {
+	case mariadb && opts.Analyze && text:
+		stmt = "ANALYZE " + query
+	case mariadb && opts.Analyze:
+		stmt = "ANALYZE FORMAT=JSON " + query
+	case opts.Analyze && opts.Format == ExplainJSON:
+		return nil, errors.New("EXPLAIN ANALYZE in JSON is not supported by MySQL")
+	case opts.Analyze && m.before(8, 0, 18):
+		return nil, errors.New("EXPLAIN ANALYZE is not supported by MySQL before 8.0.18")
+	case opts.Analyze:
+		return explainText(c, "EXPLAIN ANALYZE "+query, args...)
+	case (mariadb || m.before(8, 0, 16)) && text:
+		stmt = "EXPLAIN " + query
+	case text:
+		stmt = "EXPLAIN FORMAT=TREE " + query
+	default:
+		stmt = "EXPLAIN FORMAT=JSON " + query
+	}
+	if text {
+		return explainText(c, stmt, args...)
+	}
+	return explainJSON(c, mysqlPlanNodes, stmt, args...)
+}
+
+// IsRetryable tells if the error is a deadlock (1213) or a lock wait timeout
+// (1205), after which the transaction should be retried.
+func (m *mysql) IsRetryable(err error) bool {
+	var myErr *_mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		return false
+	}
+	return myErr.Number == 1213 || myErr.Number == 1205
+}
+
+func (m *mysql) TranslateError(err error) error {
+	return translateMySQLError(err)
+}
+
+var (
+	mysqlDuplicateKey = regexp.MustCompile(`for key '([^']+)'`)
+	mysqlConstraint   = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+	mysqlColumn       = regexp.MustCompile(`^(?:Column|Field) '([^']+)'`)
+	mysqlCheck        = regexp.MustCompile(`^Check constraint '([^']+)'`)
+)
+
+// translateMySQLError maps the errors of MySQL and MariaDB from their error
+// numbers.
+func translateMySQLError(err error) error {
+	var myErr *_mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		return err
+	}
+	switch myErr.Number {
+	case 1062:
+		e := &ErrUniqueViolation{Err: err}
+		if m := mysqlDuplicateKey.FindStringSubmatch(myErr.Message); m != nil {
+			// MySQL 8 prefixes the key with the table.
+			e.Constraint = m[1][strings.LastIndex(m[1], ".")+1:]
+		}
+		return e
+	case 1451, 1452:
+		e := &ErrForeignKeyViolation{Err: err}
+		if m := mysqlConstraint.FindStringSubmatch(myErr.Message); m != nil {
+			e.Constraint = m[1]
+		}
+		return e
+	case 1048, 1364:
+		e := &ErrNotNullViolation{Err: err}
+		if m := mysqlColumn.FindStringSubmatch(myErr.Message); m != nil {
+			e.Column = m[1]
+		}
+		return e
+	case 3819, 4025:
+		e := &ErrCheckViolation{Err: err}
+		if m := mysqlCheck.FindStringSubmatch(myErr.Message); m != nil {
+			e.Constraint = m[1]
+		} else if m := mysqlConstraint.FindStringSubmatch(myErr.Message); m != nil {
+			e.Constraint = m[1]
+		}
+		return e
+	case 1213:
+		return &wrappedError{sentinel: ErrDeadlock, err: err}
+	case 3024, 1969:
+		return &wrappedError{sentinel: ErrTimeout, err: err}
+	}
+	return err
+}
+
 func (m *mysql) Destroy(c *Connection, model *Model) error {
 	stmt := fmt.Sprintf("DELETE FROM %s  WHERE %s = ?", m.Quote(model.TableName()), model.IDField())
-	_, err := genericExec(c, stmt, model.ID())
+	_, err := GenericExec(c, stmt, model.ID())
 	if err != nil {
 		return fmt.Errorf("mysql destroy: %w", err)
 	}
@@ -122,19 +364,19 @@ func (m *mysql) Delete(c *Connection, model *Model, query Query) error {
 	// * Spaces are intentionally added to make it easy to see on the log.
 	sqlQuery = asRegex.ReplaceAllString(sqlQuery, "  ")
 
-	_, err := genericExec(c, sqlQuery, args...)
+	_, err := GenericExec(c, sqlQuery, args...)
 	return err
 }
 
 func (m *mysql) SelectOne(c *Connection, model *Model, query Query) error {
-	if err := genericSelectOne(c, model, query); err != nil {
+	if err := GenericSelectOne(c, model, query); err != nil {
 		return fmt.Errorf("mysql select one: %w", err)
 	}
 	return nil
 }
 
 func (m *mysql) SelectMany(c *Connection, models *Model, query Query) error {
-	if err := genericSelectMany(c, models, query); err != nil {
+	if err := GenericSelectMany(c, models, query); err != nil {
 		return fmt.Errorf("mysql select many: %w", err)
 	}
 	return nil
@@ -197,12 +439,12 @@ func (m *mysql) DumpSchema(w io.Writer) error {
 	if deets.Port == "socket" {
 		cmd = exec.Command("mysqldump", "-d", "-S", deets.Host, "-u", deets.User, fmt.Sprintf("--password=%s", deets.Password), deets.Database)
 	}
-	return genericDumpSchema(deets, cmd, w)
+	return GenericDumpSchema(deets, cmd, w)
 }
 
 // LoadSchema executes a schema sql file against the configured database.
 func (m *mysql) LoadSchema(r io.Reader) error {
-	return genericLoadSchema(m, r)
+	return GenericLoadSchema(m, r)
 }
 
 // TruncateAll truncates all tables for the given connection.
@@ -226,7 +468,7 @@ func (m *mysql) TruncateAll(tx *Connection) error {
 	return tx.RawQuery(qb.String()).Exec()
 }
 
-func newMySQL(deets *ConnectionDetails) (dialect, error) {
+func newMySQL(deets *ConnectionDetails) (Dialect, error) {
 	cd := &mysql{
 		commonDialect: commonDialect{ConnectionDetails: deets},
 	}
@@ -268,6 +510,15 @@ func finalizerMySQL(cd *ConnectionDetails) {
 		"readTimeout": "3s",
 		"collation":   "utf8mb4_general_ci",
 	}
+	// the driver sets the unknown options as system variables when each
+	// connection of the pool is opened. MySQL only bounds SELECT statements.
+	if d := cd.statementTimeout(); d > 0 {
+		if CanonicalDialect(cd.Dialect) == nameMariaDB {
+			defs["max_statement_time"] = strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+		} else {
+			defs["max_execution_time"] = strconv.FormatInt(d.Milliseconds(), 10)
+		}
+	}
 	forced := map[string]string{
 		"parseTime":       "true",
 		"multiStatements": "true",
@@ -1,11 +1,14 @@
 package pop
 
 import (
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
 
+	_mysql "github.com/go-sql-driver/mysql"
 	"github.com/gobuffalo/fizz"
 	"github.com/gobuffalo/fizz/translators"
 	"github.com/stretchr/testify/require"
@@ -20,7 +23,7 @@ func Test_MySQL_URL_As_Is(t *testing.T) {
 	err := cd.Finalize()
 	r.NoError(err)
 
-	m := &mysql{commonDialect{ConnectionDetails: cd}}
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: cd}}
 	r.Equal("user:pass@(host:port)/dbase?opt=value", m.URL())
 	r.Equal("user:pass@(host:port)/?opt=value", m.urlWithoutDb())
 	r.Equal("user:pass@(host:port)/dbase?opt=value", m.MigrationURL())
@@ -40,7 +43,7 @@ func Test_MySQL_URL_Override_withURL(t *testing.T) {
 	err := cd.Finalize()
 	r.NoError(err)
 
-	m := &mysql{commonDialect{ConnectionDetails: cd}}
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: cd}}
 	r.Equal("user:pass@(host:port)/dbase?opt=value", m.URL())
 	r.Equal("user:pass@(host:port)/?opt=value", m.urlWithoutDb())
 	r.Equal("user:pass@(host:port)/dbase?opt=value", m.MigrationURL())
@@ -48,7 +51,7 @@ func Test_MySQL_URL_Override_withURL(t *testing.T) {
 
 func Test_MySQL_URL_With_Values(t *testing.T) {
 	r := require.New(t)
-	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{
 		Database: "dbase",
 		Host:     "host",
 		Port:     "port",
@@ -63,7 +66,7 @@ func Test_MySQL_URL_With_Values(t *testing.T) {
 
 func Test_MySQL_URL_Without_User(t *testing.T) {
 	r := require.New(t)
-	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{
 		Password: "pass",
 		Database: "dbase",
 	}}}
@@ -74,7 +77,7 @@ func Test_MySQL_URL_Without_User(t *testing.T) {
 
 func Test_MySQL_URL_Without_Password(t *testing.T) {
 	r := require.New(t)
-	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{
 		User:     "user",
 		Database: "dbase",
 	}}}
@@ -125,7 +128,7 @@ func Test_MySQL_URL_urlParserMySQL_Socket(t *testing.T) {
 
 	// additional test without URL
 	cd.URL = ""
-	m := &mysql{commonDialect{ConnectionDetails: cd}}
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: cd}}
 	r.True(strings.HasPrefix(m.URL(), "unix(/tmp/socket)/dbase?"))
 	r.True(strings.HasPrefix(m.urlWithoutDb(), "unix(/tmp/socket)/?"))
 }
@@ -141,7 +144,7 @@ func Test_MySQL_URL_urlParserMySQL_Unsupported(t *testing.T) {
 
 func Test_MySQL_Database_Open_Failure(t *testing.T) {
 	r := require.New(t)
-	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{}}}
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}
 	err := m.CreateDB()
 	r.Error(err)
 	err = m.DropDB()
@@ -151,7 +154,7 @@ func Test_MySQL_Database_Open_Failure(t *testing.T) {
 func Test_MySQL_FizzTranslator(t *testing.T) {
 	r := require.New(t)
 	cd := &ConnectionDetails{}
-	m := &mysql{commonDialect{ConnectionDetails: cd}}
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: cd}}
 	ft := m.FizzTranslator()
 	r.IsType(&translators.MySQL{}, ft)
 	r.Implements((*fizz.Translator)(nil), ft)
@@ -159,7 +162,7 @@ func Test_MySQL_FizzTranslator(t *testing.T) {
 
 func Test_MySQL_Finalizer_Default_CD(t *testing.T) {
 	r := require.New(t)
-	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{}}}
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}
 	finalizerMySQL(m.ConnectionDetails)
 	r.Equal(hostMySQL, m.ConnectionDetails.Host)
 	r.Equal(portMySQL, m.ConnectionDetails.Port)
@@ -167,7 +170,7 @@ func Test_MySQL_Finalizer_Default_CD(t *testing.T) {
 
 func Test_MySQL_Finalizer_Default_Options(t *testing.T) {
 	r := require.New(t)
-	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{}}}
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}
 	finalizerMySQL(m.ConnectionDetails)
 	r.Contains(m.URL(), "multiStatements=true")
 	r.Contains(m.URL(), "parseTime=true")
@@ -177,7 +180,7 @@ func Test_MySQL_Finalizer_Default_Options(t *testing.T) {
 
 func Test_MySQL_Finalizer_Preserve_User_Defined_Options(t *testing.T) {
 	r := require.New(t)
-	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{
 		Options: map[string]string{
 			"multiStatements": "false",
 			"parseTime":       "false",
@@ -244,3 +247,24 @@ func (s *MySQLSuite) Test_MySQL_DDL_Schema() {
 	err = PDB.Dialect.DumpSchema(f)
 	r.Error(err)
 }
+
+func Test_MySQL_IsRetryable(t *testing.T) {
+	r := require.New(t)
+
+	m := &mysql{}
+	r.True(m.IsRetryable(&_mysql.MySQLError{Number: 1213}))
+	r.True(m.IsRetryable(fmt.Errorf("commit: %w", &_mysql.MySQLError{Number: 1205})))
+	r.False(m.IsRetryable(&_mysql.MySQLError{Number: 1062}))
+	r.False(m.IsRetryable(errors.New("deadlock")))
+}
+
+func Test_MySQL_before(t *testing.T) {
+	r := require.New(t)
+
+	r.False((&mysql{}).before(8, 0, 0))
+	r.True((&mysql{version: "5.7.44-log"}).before(8, 0, 0))
+	r.False((&mysql{version: "8.0.35"}).before(8, 0, 0))
+	r.True((&mysql{version: "8.0.15"}).before(8, 0, 16))
+	r.False((&mysql{version: "8.0.16"}).before(8, 0, 16))
+	r.False((&mysql{version: "10.3.39-MariaDB"}).before(8, 0, 0))
+}
@@ -2,10 +2,13 @@ package pop
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
 	"os/exec"
+	"regexp"
+	"strings"
 	"sync"
 
 	"github.com/gobuffalo/fizz"
@@ -31,7 +34,7 @@ func init() {
 	newConnection[namePostgreSQL] = newPostgreSQL
 }
 
-var _ dialect = &postgresql{}
+var _ Dialect = &postgresql{}
 
 type postgresql struct {
 	commonDialect
@@ -66,7 +69,7 @@ func (p *postgresql) Create(c *Connection, model *Model, cols columns.Columns) e
 		} else {
 			query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING %s", p.Quote(model.TableName()), model.IDField())
 		}
-		txlog(logging.SQL, c, query, model.Value)
+		c.log(logging.SQL, query, model.Value)
 		rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
 		if err != nil {
 			return fmt.Errorf("named insert: %w", err)
@@ -88,20 +91,98 @@ func (p *postgresql) Create(c *Connection, model *Model, cols columns.Columns) e
 		model.setID(id)
 		return nil
 	}
-	return genericCreate(c, model, cols, p)
+	return GenericCreate(c, model, cols, p)
+}
+
+func (p *postgresql) CreateMany(c *Connection, models []*Model, cols columns.Columns) error {
+	return GenericCreateManyReturning(c, models, cols, p)
+}
+
+func (p *postgresql) Upsert(c *Connection, model *Model, cols columns.Columns, opts UpsertOptions) error {
+	if err := GenericUpsertReturning(c, model, cols, opts, p); err != nil {
+		return fmt.Errorf("postgres upsert: %w", err)
+	}
+	return nil
 }
 
 func (p *postgresql) Update(c *Connection, model *Model, cols columns.Columns) error {
-	return genericUpdate(c, model, cols, p)
+	return GenericUpdate(c, model, cols, p)
 }
 
 func (p *postgresql) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
-	return genericUpdateQuery(c, model, cols, p, query, sqlx.DOLLAR)
+	return GenericUpdateQuery(c, model, cols, p, query, sqlx.DOLLAR)
+}
+
+func (p *postgresql) LockClause(l RowLock) string {
+	return genericLockClause(l)
+}
+
+func (p *postgresql) Explain(c *Connection, query string, args []interface{}, opts ExplainOptions) (*ExplainPlan, error) {
+	options := []string{}
+	if opts.Analyze {
+		options = append(options, "ANALYZE")
+	}
+	if opts.Format == ExplainText {
+		options = append(options, "FORMAT TEXT")
+		return explainText(c, fmt.Sprintf("EXPLAIN (%s) %s", strings.Join(options, ", "), query), args...)
+	}
+	options = append(options, "FORMAT JSON")
+	return explainJSON(c, postgresPlanNodes, fmt.Sprintf("EXPLAIN (%s) %s", strings.Join(options, ", "), query), args...)
+}
+
+func (p *postgresql) IsRetryable(err error) bool {
+	return isRetryablePgError(err)
+}
+
+// isRetryablePgError tells if the error is a serialization failure or a
+// deadlock, after which the transaction should be retried.
+func isRetryablePgError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+func (p *postgresql) TranslateError(err error) error {
+	return translatePgError(err)
+}
+
+var pgKeyColumns = regexp.MustCompile(`^Key \((.+?)\)=`)
+
+// translatePgError maps the errors of postgres and CockroachDB from their
+// SQLSTATE codes.
+func translatePgError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case "23505":
+		e := &ErrUniqueViolation{Constraint: pgErr.ConstraintName, Err: err}
+		if m := pgKeyColumns.FindStringSubmatch(pgErr.Detail); m != nil {
+			e.Columns = splitColumns(m[1])
+		}
+		return e
+	case "23503":
+		return &ErrForeignKeyViolation{Constraint: pgErr.ConstraintName, Err: err}
+	case "23502":
+		return &ErrNotNullViolation{Column: pgErr.ColumnName, Err: err}
+	case "23514":
+		return &ErrCheckViolation{Constraint: pgErr.ConstraintName, Err: err}
+	case "40P01":
+		return &wrappedError{sentinel: ErrDeadlock, err: err}
+	case "40001":
+		return &wrappedError{sentinel: ErrSerialization, err: err}
+	case "57014":
+		return &wrappedError{sentinel: ErrTimeout, err: err}
+	}
+	return err
 }
 
 func (p *postgresql) Destroy(c *Connection, model *Model) error {
 	stmt := p.TranslateSQL(fmt.Sprintf("DELETE FROM %s AS %s WHERE %s", p.Quote(model.TableName()), model.Alias(), model.WhereID()))
-	_, err := genericExec(c, stmt, model.ID())
+	_, err := GenericExec(c, stmt, model.ID())
 	if err != nil {
 		return err
 	}
@@ -109,15 +190,15 @@ func (p *postgresql) Destroy(c *Connection, model *Model) error {
 }
 
 func (p *postgresql) Delete(c *Connection, model *Model, query Query) error {
-	return genericDelete(c, model, query)
+	return GenericDelete(c, model, query)
 }
 
 func (p *postgresql) SelectOne(c *Connection, model *Model, query Query) error {
-	return genericSelectOne(c, model, query)
+	return GenericSelectOne(c, model, query)
 }
 
 func (p *postgresql) SelectMany(c *Connection, models *Model, query Query) error {
-	return genericSelectMany(c, models, query)
+	return GenericSelectMany(c, models, query)
 }
 
 func (p *postgresql) CreateDB() error {
@@ -203,12 +284,12 @@ func (p *postgresql) FizzTranslator() fizz.Translator {
 
 func (p *postgresql) DumpSchema(w io.Writer) error {
 	cmd := exec.Command("pg_dump", "-s", fmt.Sprintf("--dbname=%s", p.URL()))
-	return genericDumpSchema(p.Details(), cmd, w)
+	return GenericDumpSchema(p.Details(), cmd, w)
 }
 
 // LoadSchema executes a schema sql file against the configured database.
 func (p *postgresql) LoadSchema(r io.Reader) error {
-	return genericLoadSchema(p, r)
+	return GenericLoadSchema(p, r)
 }
 
 // TruncateAll truncates all tables for the given connection.
@@ -216,7 +297,7 @@ func (p *postgresql) TruncateAll(tx *Connection) error {
 	return tx.RawQuery(fmt.Sprintf(pgTruncate, tx.MigrationTableName())).Exec()
 }
 
-func newPostgreSQL(deets *ConnectionDetails) (dialect, error) {
+func newPostgreSQL(deets *ConnectionDetails) (Dialect, error) {
 	cd := &postgresql{
 		commonDialect:  commonDialect{ConnectionDetails: deets},
 		translateCache: map[string]string{},
@@ -240,7 +321,7 @@ func urlParserPostgreSQL(cd *ConnectionDetails) error {
 	cd.Password = conf.Password
 	cd.Port = fmt.Sprintf("%d", conf.Port)
 
-	options := []string{"fallback_application_name"}
+	options := []string{"fallback_application_name", "statement_timeout"}
 	for i := range options {
 		if opt, ok := conf.RuntimeParams[options[i]]; ok {
 			cd.setOption(options[i], opt)
@@ -1,10 +1,13 @@
 package pop
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"os/user"
 	"testing"
 
+	"github.com/jackc/pgconn"
 	"github.com/stretchr/testify/require"
 )
 
@@ -127,3 +130,13 @@ func Test_PostgreSQL_Quotable(t *testing.T) {
 	r.Equal(`"schema"."table_name"`, p.Quote("schema.table_name"))
 	r.Equal(`"schema"."table name"`, p.Quote(`"schema"."table name"`))
 }
+
+func Test_PostgreSQL_IsRetryable(t *testing.T) {
+	r := require.New(t)
+
+	p := &postgresql{}
+	r.True(p.IsRetryable(&pgconn.PgError{Code: "40001"}))
+	r.True(p.IsRetryable(fmt.Errorf("commit: %w", &pgconn.PgError{Code: "40P01"})))
+	r.False(p.IsRetryable(&pgconn.PgError{Code: "23505"}))
+	r.False(p.IsRetryable(errors.New("40001")))
+}
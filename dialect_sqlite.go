@@ -10,12 +10,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gobuffalo/fizz"
 	"github.com/gobuffalo/fizz/translators"
+	"github.com/gobuffalo/nulls"
 	"github.com/gobuffalo/pop/v6/columns"
 	"github.com/gobuffalo/pop/v6/internal/defaults"
 	"github.com/gobuffalo/pop/v6/logging"
@@ -32,7 +34,7 @@ func init() {
 	finalizer[nameSQLite3] = finalizerSQLite
 }
 
-var _ dialect = &sqlite{}
+var _ Dialect = &sqlite{}
 
 type sqlite struct {
 	commonDialect
@@ -87,7 +89,7 @@ func (m *sqlite) Create(c *Connection, model *Model, cols columns.Columns) error
 			} else {
 				query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", m.Quote(model.TableName()))
 			}
-			txlog(logging.SQL, c, query, model.Value)
+			c.log(logging.SQL, query, model.Value)
 			res, err := c.Store.NamedExecContext(model.ctx, query, model.Value)
 			if err != nil {
 				return err
@@ -101,16 +103,37 @@ func (m *sqlite) Create(c *Connection, model *Model, cols columns.Columns) error
 			}
 			return nil
 		}
-		if err := genericCreate(c, model, cols, m); err != nil {
+		if err := GenericCreate(c, model, cols, m); err != nil {
 			return fmt.Errorf("sqlite create: %w", err)
 		}
 		return nil
 	})
 }
 
+func (m *sqlite) CreateMany(c *Connection, models []*Model, cols columns.Columns) error {
+	return m.locker(m.smGil, func() error {
+		// LastInsertId reports the last row of a multi-row insert in SQLite.
+		// The IDs are consecutive since the database is locked for writing,
+		// contrary to the order of RETURNING rows, which is arbitrary.
+		if err := genericCreateMany(c, models, cols, m, true, 1); err != nil {
+			return fmt.Errorf("sqlite create many: %w", err)
+		}
+		return nil
+	})
+}
+
+func (m *sqlite) Upsert(c *Connection, model *Model, cols columns.Columns, opts UpsertOptions) error {
+	return m.locker(m.smGil, func() error {
+		if err := GenericUpsertReturning(c, model, cols, opts, m); err != nil {
+			return fmt.Errorf("sqlite upsert: %w", err)
+		}
+		return nil
+	})
+}
+
 func (m *sqlite) Update(c *Connection, model *Model, cols columns.Columns) error {
 	return m.locker(m.smGil, func() error {
-		if err := genericUpdate(c, model, cols, m); err != nil {
+		if err := GenericUpdate(c, model, cols, m); err != nil {
 			return fmt.Errorf("sqlite update: %w", err)
 		}
 		return nil
@@ -120,7 +143,7 @@ func (m *sqlite) Update(c *Connection, model *Model, cols columns.Columns) error
 func (m *sqlite) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
 	rowsAffected := int64(0)
 	err := m.locker(m.smGil, func() error {
-		if n, err := genericUpdateQuery(c, model, cols, m, query, sqlx.QUESTION); err != nil {
+		if n, err := GenericUpdateQuery(c, model, cols, m, query, sqlx.QUESTION); err != nil {
 			rowsAffected = n
 			return fmt.Errorf("sqlite update query: %w", err)
 		} else {
@@ -131,9 +154,13 @@ func (m *sqlite) UpdateQuery(c *Connection, model *Model, cols columns.Columns,
 	return rowsAffected, err
 }
 
+func (m *sqlite) TranslateError(err error) error {
+	return translateSQLiteError(err)
+}
+
 func (m *sqlite) Destroy(c *Connection, model *Model) error {
 	return m.locker(m.smGil, func() error {
-		if err := genericDestroy(c, model, m); err != nil {
+		if err := GenericDestroy(c, model, m); err != nil {
 			return fmt.Errorf("sqlite destroy: %w", err)
 		}
 		return nil
@@ -141,12 +168,12 @@ func (m *sqlite) Destroy(c *Connection, model *Model) error {
 }
 
 func (m *sqlite) Delete(c *Connection, model *Model, query Query) error {
-	return genericDelete(c, model, query)
+	return GenericDelete(c, model, query)
 }
 
 func (m *sqlite) SelectOne(c *Connection, model *Model, query Query) error {
 	return m.locker(m.smGil, func() error {
-		if err := genericSelectOne(c, model, query); err != nil {
+		if err := GenericSelectOne(c, model, query); err != nil {
 			return fmt.Errorf("sqlite select one: %w", err)
 		}
 		return nil
@@ -155,13 +182,68 @@ func (m *sqlite) SelectOne(c *Connection, model *Model, query Query) error {
 
 func (m *sqlite) SelectMany(c *Connection, models *Model, query Query) error {
 	return m.locker(m.smGil, func() error {
-		if err := genericSelectMany(c, models, query); err != nil {
+		if err := GenericSelectMany(c, models, query); err != nil {
 			return fmt.Errorf("sqlite select many: %w", err)
 		}
 		return nil
 	})
 }
 
+// Explain returns the plan of EXPLAIN QUERY PLAN, which SQLite only gives
+// as rows of nodes.
+func (m *sqlite) Explain(c *Connection, query string, args []interface{}, opts ExplainOptions) (*ExplainPlan, error) {
+	if opts.Analyze {
+		return nil, errors.New("EXPLAIN ANALYZE is not supported by SQLite")
+	}
+	if opts.Format == ExplainJSON {
+		return nil, errors.New("EXPLAIN in JSON is not supported by SQLite")
+	}
+	return explainSQLite(c, "EXPLAIN QUERY PLAN "+query, args...)
+}
+
+// WrapScalar parses the timestamps selected into a time, since the driver
+// only returns a time for the columns declared as timestamps, and returns the
+// result of MIN(created_at) as text.
+func (m *sqlite) WrapScalar(dest interface{}) interface{} {
+	switch dest.(type) {
+	case *time.Time, *sql.NullTime, *nulls.Time:
+		return &sqliteTime{dest: dest}
+	}
+	return dest
+}
+
+// sqliteTime scans a timestamp stored as text into a time.
+type sqliteTime struct {
+	dest interface{}
+}
+
+func (t *sqliteTime) Scan(src interface{}) error {
+	if b, ok := src.([]byte); ok {
+		src = string(b)
+	}
+	if s, ok := src.(string); ok {
+		s = strings.TrimSuffix(s, "Z")
+		for _, format := range sqliteTimestampFormats {
+			if ts, err := time.ParseInLocation(format, s, time.UTC); err == nil {
+				src = ts
+				break
+			}
+		}
+	}
+
+	switch d := t.dest.(type) {
+	case sql.Scanner:
+		return d.Scan(src)
+	case *time.Time:
+		ts, ok := src.(time.Time)
+		if !ok {
+			return fmt.Errorf("couldn't scan %T into time.Time", src)
+		}
+		*d = ts
+	}
+	return nil
+}
+
 func (m *sqlite) Lock(fn func() error) error {
 	return m.locker(m.gil, fn)
 }
@@ -230,7 +312,7 @@ func (m *sqlite) FizzTranslator() fizz.Translator {
 
 func (m *sqlite) DumpSchema(w io.Writer) error {
 	cmd := exec.Command("sqlite3", m.Details().Database, ".schema")
-	return genericDumpSchema(m.Details(), cmd, w)
+	return GenericDumpSchema(m.Details(), cmd, w)
 }
 
 func (m *sqlite) LoadSchema(r io.Reader) error {
@@ -278,7 +360,7 @@ func (m *sqlite) TruncateAll(tx *Connection) error {
 	return tx.RawQuery(strings.Join(stmts, "; ")).Exec()
 }
 
-func newSQLite(deets *ConnectionDetails) (dialect, error) {
+func newSQLite(deets *ConnectionDetails) (Dialect, error) {
 	err := requireSQLite3()
 	if err != nil {
 		return nil, err
@@ -320,6 +402,11 @@ func finalizerSQLite(cd *ConnectionDetails) {
 	defs := map[string]string{
 		"_busy_timeout": "5000",
 	}
+	// SQLite has no statement timeout, but waits for the locks of other
+	// connections for up to the busy timeout.
+	if d := cd.statementTimeout(); d > 0 {
+		defs["_busy_timeout"] = strconv.FormatInt(d.Milliseconds(), 10)
+	}
 	forced := map[string]string{
 		"_fk": "true",
 	}
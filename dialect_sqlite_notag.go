@@ -0,0 +1,14 @@
+//go:build !sqlite
+// +build !sqlite
+
+package pop
+
+// translateSQLiteError leaves the errors untouched, since the SQLite driver
+// is only loaded with the sqlite build tag.
+func translateSQLiteError(err error) error {
+	return err
+}
+
+// sqliteTimestampFormats are the formats of the timestamps stored by the
+// driver, which is not loaded.
+var sqliteTimestampFormats []string
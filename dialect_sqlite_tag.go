@@ -4,5 +4,47 @@
 package pop
 
 import (
-	_ "github.com/mattn/go-sqlite3" // Load SQLite3 CGo driver
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3" // Load SQLite3 CGo driver
 )
+
+// translateSQLiteError maps the errors of SQLite from their extended codes.
+// The messages are of the form "UNIQUE constraint failed: users.email".
+func translateSQLiteError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+	detail := ""
+	if i := strings.Index(sqliteErr.Error(), "constraint failed: "); i >= 0 {
+		detail = sqliteErr.Error()[i+len("constraint failed: "):]
+	}
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		e := &ErrUniqueViolation{Err: err}
+		if detail != "" {
+			e.Columns = splitColumns(detail)
+		}
+		return e
+	case sqlite3.ErrConstraintForeignKey:
+		return &ErrForeignKeyViolation{Err: err}
+	case sqlite3.ErrConstraintNotNull:
+		e := &ErrNotNullViolation{Err: err}
+		if detail != "" {
+			e.Column = splitColumns(detail)[0]
+		}
+		return e
+	case sqlite3.ErrConstraintCheck:
+		return &ErrCheckViolation{Constraint: detail, Err: err}
+	}
+	if sqliteErr.Code == sqlite3.ErrBusy {
+		return &wrappedError{sentinel: ErrTimeout, err: err}
+	}
+	return err
+}
+
+// sqliteTimestampFormats are the formats of the timestamps stored by the
+// driver.
+var sqliteTimestampFormats = sqlite3.SQLiteTimestampFormats
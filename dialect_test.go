@@ -9,7 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func Test_genericDumpSchema(t *testing.T) {
+func Test_GenericDumpSchema(t *testing.T) {
 	table := []struct {
 		cmd *exec.Cmd
 		err bool
@@ -22,7 +22,7 @@ func Test_genericDumpSchema(t *testing.T) {
 		t.Run(strings.Join(tt.cmd.Args, " "), func(st *testing.T) {
 			r := require.New(st)
 			bb := &bytes.Buffer{}
-			err := genericDumpSchema(&ConnectionDetails{}, tt.cmd, bb)
+			err := GenericDumpSchema(&ConnectionDetails{}, tt.cmd, bb)
 			if tt.err {
 				r.Error(err)
 				return
@@ -32,3 +32,44 @@ func Test_genericDumpSchema(t *testing.T) {
 		})
 	}
 }
+
+func Test_RegisterDialect(t *testing.T) {
+	r := require.New(t)
+
+	const name = "registered_test"
+	t.Cleanup(func() { unregisterDialect(name) })
+	finalized := false
+	err := RegisterDialect(name, newPostgreSQL, nil, func(cd *ConnectionDetails) {
+		finalized = true
+		cd.Port = "1234"
+	})
+	r.NoError(err)
+	r.True(DialectSupported(name))
+
+	c, err := NewConnection(&ConnectionDetails{
+		Dialect:  name,
+		Database: "pop_test",
+		Host:     "127.0.0.1",
+	})
+	r.NoError(err)
+	r.True(finalized)
+	r.Equal("1234", c.Dialect.Details().Port)
+	r.Equal(namePostgreSQL, c.Dialect.Name())
+
+	r.Error(RegisterDialect(name, newPostgreSQL, nil, nil))
+	r.Error(RegisterDialect("", newPostgreSQL, nil, nil))
+	r.Error(RegisterDialect("registered_test_nil", nil, nil, nil))
+}
+
+// unregisterDialect removes a dialect registered by a test.
+func unregisterDialect(name string) {
+	for i, d := range AvailableDialects {
+		if d == name {
+			AvailableDialects = append(AvailableDialects[:i:i], AvailableDialects[i+1:]...)
+			break
+		}
+	}
+	delete(newConnection, name)
+	delete(urlParser, name)
+	delete(finalizer, name)
+}
@@ -0,0 +1,589 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobuffalo/fizz"
+	"github.com/gobuffalo/pop/v6/columns"
+	"github.com/gobuffalo/pop/v6/internal/defaults"
+	"github.com/gobuffalo/pop/v6/logging"
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const nameYDB = "ydb"
+const hostYDB = "localhost"
+const portYDB = "2136"
+
+func init() {
+	AvailableDialects = append(AvailableDialects, nameYDB)
+	dialectSynonyms["grpc"] = nameYDB
+	dialectSynonyms["grpcs"] = nameYDB
+	urlParser[nameYDB] = urlParserYDB
+	finalizer[nameYDB] = finalizerYDB
+	newConnection[nameYDB] = newYDB
+}
+
+var _ Dialect = &ydb{}
+
+// ydb is the dialect for YDB (https://ydb.tech). Pop does not ship the
+// driver; the application has to import a database/sql driver registered
+// as "ydb", e.g. github.com/ydb-platform/ydb-go-sdk/v3, or set
+// `ConnectionDetails.Driver`.
+//
+// YDB has no auto increment, so integer primary keys must be set before
+// calling Create. Set the "write_mode" option to "upsert" to make Create
+// issue UPSERT statements instead of INSERT.
+type ydb struct {
+	commonDialect
+	translateCache map[string]string
+	mu             sync.Mutex
+}
+
+func requireYDB(driverName string) error {
+	for _, n := range sql.Drivers() {
+		if n == driverName {
+			return nil
+		}
+	}
+	return fmt.Errorf("no %s driver is registered, import github.com/ydb-platform/ydb-go-sdk/v3 to use YDB", driverName)
+}
+
+func (y *ydb) Name() string {
+	return nameYDB
+}
+
+func (y *ydb) DefaultDriver() string {
+	return nameYDB
+}
+
+func (y *ydb) Details() *ConnectionDetails {
+	return y.ConnectionDetails
+}
+
+// Quote quotes each part of a dotted identifier with backticks.
+func (y *ydb) Quote(key string) string {
+	parts := strings.Split(key, ".")
+
+	for i, part := range parts {
+		part = strings.Trim(part, "`")
+		part = strings.TrimSpace(part)
+
+		parts[i] = fmt.Sprintf("`%v`", part)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func (y *ydb) URL() string {
+	cd := y.ConnectionDetails
+	if cd.URL != "" {
+		if strings.HasPrefix(cd.URL, nameYDB+"://") {
+			return "grpc://" + strings.TrimPrefix(cd.URL, nameYDB+"://")
+		}
+		return cd.URL
+	}
+
+	scheme := "grpc"
+	if cd.option("secure") == "true" {
+		scheme = "grpcs"
+	}
+	u := fmt.Sprintf("%s://%s:%s/%s", scheme, cd.Host, cd.Port, strings.TrimPrefix(cd.Database, "/"))
+	if opts := cd.OptionsString(""); opts != "" {
+		u = u + "?" + opts
+	}
+	return u
+}
+
+func (y *ydb) MigrationURL() string {
+	return y.URL()
+}
+
+func (y *ydb) Create(c *Connection, model *Model, cols columns.Columns) error {
+	keyType, err := model.PrimaryKeyType()
+	if err != nil {
+		return err
+	}
+	switch keyType {
+	case "UUID":
+		if model.ID() == emptyUUID {
+			u, err := uuid.NewV4()
+			if err != nil {
+				return err
+			}
+			model.setID(u)
+		}
+	case "string":
+		if model.ID() == "" {
+			return fmt.Errorf("missing ID value")
+		}
+	default:
+		// YDB has no auto increment and no LastInsertId, so the ID must be
+		// provided by the caller.
+		if IsZeroOfUnderlyingType(model.ID()) {
+			return fmt.Errorf("ydb create: missing ID value, YDB can not generate %s primary keys", keyType)
+		}
+	}
+
+	verb := "INSERT"
+	if y.Details().option("write_mode") == "upsert" {
+		verb = "UPSERT"
+	}
+
+	w := cols.Writeable()
+	w.Add(model.IDField())
+	query := fmt.Sprintf("%s INTO %s (%s) VALUES (%s)", verb, y.Quote(model.TableName()), w.QuotedString(y), w.SymbolizedString())
+	c.log(logging.SQL, query, model.Value)
+	if _, err := c.Store.NamedExecContext(model.ctx, query, model.Value); err != nil {
+		return fmt.Errorf("ydb create: %w", err)
+	}
+	return nil
+}
+
+func (y *ydb) Update(c *Connection, model *Model, cols columns.Columns) error {
+	set, version := versionedUpdateString(model, cols, y)
+	// YQL does not support table aliases in UPDATE statements.
+	where := fmt.Sprintf("%s = :%s", y.Quote(model.IDField()), model.IDField())
+	if version != "" {
+		where = fmt.Sprintf("%s AND %s = :%s", where, y.Quote(version), version)
+	}
+	stmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s", y.Quote(model.TableName()), set, where)
+	c.log(logging.SQL, stmt, model.ID())
+	res, err := c.Store.NamedExecContext(model.ctx, stmt, model.Value)
+	if err != nil {
+		return fmt.Errorf("ydb update: %w", err)
+	}
+	if version != "" {
+		return checkVersion(model, res)
+	}
+	return nil
+}
+
+func (y *ydb) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
+	q := fmt.Sprintf("UPDATE %s SET %s", y.Quote(model.TableName()), cols.Writeable().QuotedUpdateString(y))
+
+	q, updateArgs, err := sqlx.Named(q, model.Value)
+	if err != nil {
+		return 0, err
+	}
+
+	sb := query.toSQLBuilder(model)
+	q = y.TranslateSQL(sb.buildWhereClauses(q))
+
+	result, err := GenericExec(c, q, append(updateArgs, sb.args...)...)
+	if err != nil {
+		return 0, fmt.Errorf("ydb update query: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (y *ydb) Destroy(c *Connection, model *Model) error {
+	stmt := y.TranslateSQL(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", y.Quote(model.TableName()), y.Quote(model.IDField())))
+	if _, err := GenericExec(c, stmt, model.ID()); err != nil {
+		return fmt.Errorf("ydb destroy: %w", err)
+	}
+	return nil
+}
+
+func (y *ydb) Delete(c *Connection, model *Model, query Query) error {
+	sqlQuery, args := query.ToSQL(model)
+	// YQL does not support table aliases in DELETE statements.
+	sqlQuery = asRegex.ReplaceAllString(sqlQuery, "")
+
+	_, err := GenericExec(c, sqlQuery, args...)
+	return err
+}
+
+func (y *ydb) SelectOne(c *Connection, model *Model, query Query) error {
+	return GenericSelectOne(c, model, query)
+}
+
+func (y *ydb) SelectMany(c *Connection, models *Model, query Query) error {
+	return GenericSelectMany(c, models, query)
+}
+
+func (y *ydb) CreateDB() error {
+	return fmt.Errorf("creating YDB database %s is not supported, databases are managed by the YDB cluster", y.Details().Database)
+}
+
+func (y *ydb) DropDB() error {
+	return fmt.Errorf("dropping YDB database %s is not supported, databases are managed by the YDB cluster", y.Details().Database)
+}
+
+func (y *ydb) SavepointSQL(op, name string) (string, error) {
+	return "", errors.New("nested transactions are not supported by YDB, which has no savepoints")
+}
+
+// TranslateSQL rewrites `?` bindvars to the YQL parameters `$p1`, `$p2`, ...
+// The matching DECLARE statements are added when the statement is executed,
+// since they depend on the types of the arguments.
+func (y *ydb) TranslateSQL(sql string) string {
+	defer y.mu.Unlock()
+	y.mu.Lock()
+
+	if csql, ok := y.translateCache[sql]; ok {
+		return csql
+	}
+	csql := ydbRebind(sql)
+
+	y.translateCache[sql] = csql
+	return csql
+}
+
+func (y *ydb) FizzTranslator() fizz.Translator {
+	return &ydbTranslator{}
+}
+
+func (y *ydb) DumpSchema(w io.Writer) error {
+	return errors.New("dumping the schema is not supported for YDB")
+}
+
+func (y *ydb) LoadSchema(r io.Reader) error {
+	return GenericLoadSchema(y, r)
+}
+
+func (y *ydb) TruncateAll(tx *Connection) error {
+	return errors.New("truncating all tables is not supported for YDB")
+}
+
+// WrapStore makes every statement sent through the store a valid YQL query,
+// by declaring the `$pN` parameters and passing the arguments by name.
+func (y *ydb) WrapStore(s Store) Store {
+	return ydbStore{Store: s, dialect: y}
+}
+
+func newYDB(deets *ConnectionDetails) (Dialect, error) {
+	if err := requireYDB(defaults.String(deets.Driver, nameYDB)); err != nil {
+		return nil, err
+	}
+	d := &ydb{
+		commonDialect:  commonDialect{ConnectionDetails: deets},
+		translateCache: map[string]string{},
+		mu:             sync.Mutex{},
+	}
+	return d, nil
+}
+
+// urlParserYDB parses URLs in the form of grpc(s)://host:port/database?options
+// as well as ydb://host:port/database?options.
+func urlParserYDB(cd *ConnectionDetails) error {
+	u, err := url.Parse(cd.URL)
+	if err != nil {
+		return fmt.Errorf("couldn't parse %s: %w", cd.URL, err)
+	}
+
+	cd.Database = strings.TrimPrefix(u.Path, "/")
+	cd.Host = u.Hostname()
+	cd.Port = u.Port()
+	if u.User != nil {
+		cd.User = u.User.Username()
+		cd.Password, _ = u.User.Password()
+	}
+	if u.Scheme == "grpcs" {
+		cd.setOption("secure", "true")
+	}
+	for k := range u.Query() {
+		cd.setOption(k, u.Query().Get(k))
+	}
+	return nil
+}
+
+func finalizerYDB(cd *ConnectionDetails) {
+	cd.Host = defaults.String(cd.Host, hostYDB)
+	cd.Port = defaults.String(cd.Port, portYDB)
+}
+
+// ydbRebind replaces `?` bindvars outside of quoted strings, identifiers
+// and comments with numbered YQL parameters.
+func ydbRebind(query string) string {
+	var sb strings.Builder
+	sb.Grow(len(query) + 10)
+
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Quotes are closed by the same character, unless it is escaped
+			// with a backslash.
+			end := i + 1
+			for end < len(query) && query[end] != c {
+				if query[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(query) {
+				end++
+			} else {
+				end = len(query)
+			}
+			sb.WriteString(query[i:end])
+			i = end - 1
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			sb.WriteString(query[i : i+end])
+			i += end - 1
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i
+			} else {
+				end += 4
+			}
+			sb.WriteString(query[i : i+end])
+			i += end - 1
+		case c == '?':
+			n++
+			sb.WriteString("$p")
+			sb.WriteString(strconv.Itoa(n))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// ydbBind prepends the DECLARE statements for the `$pN` parameters to the
+// query and converts the positional arguments to named ones. Queries which
+// already use named arguments are returned untouched.
+func ydbBind(query string, args []interface{}) (string, []interface{}, error) {
+	if len(args) == 0 {
+		return query, args, nil
+	}
+	for _, a := range args {
+		if _, ok := a.(sql.NamedArg); ok {
+			return query, args, nil
+		}
+	}
+
+	var declares strings.Builder
+	named := make([]interface{}, len(args))
+	for i, a := range args {
+		name := fmt.Sprintf("p%d", i+1)
+		typ, v, err := ydbType(a)
+		if err != nil {
+			return "", nil, fmt.Errorf("ydb: argument $%s: %w", name, err)
+		}
+		fmt.Fprintf(&declares, "DECLARE $%s AS %s;\n", name, typ)
+		named[i] = sql.Named(name, v)
+	}
+	return declares.String() + query, named, nil
+}
+
+var (
+	ydbTimeType     = reflect.TypeOf(time.Time{})
+	ydbDurationType = reflect.TypeOf(time.Duration(0))
+	ydbValuerType   = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+	// ydbIntTypes maps the kinds of integers to the types matching their
+	// YQL declaration.
+	ydbIntTypes = map[reflect.Kind]reflect.Type{
+		reflect.Int:    reflect.TypeOf(int64(0)),
+		reflect.Int64:  reflect.TypeOf(int64(0)),
+		reflect.Int32:  reflect.TypeOf(int32(0)),
+		reflect.Int16:  reflect.TypeOf(int16(0)),
+		reflect.Int8:   reflect.TypeOf(int8(0)),
+		reflect.Uint:   reflect.TypeOf(uint64(0)),
+		reflect.Uint64: reflect.TypeOf(uint64(0)),
+		reflect.Uint32: reflect.TypeOf(uint32(0)),
+		reflect.Uint16: reflect.TypeOf(uint16(0)),
+		reflect.Uint8:  reflect.TypeOf(uint8(0)),
+	}
+)
+
+// ydbType returns the YQL type to declare for the given argument, along with
+// the value to pass to the driver.
+func ydbType(arg interface{}) (string, interface{}, error) {
+	if arg == nil {
+		return "", nil, errors.New("can not infer the type of an untyped nil, use a nullable type instead")
+	}
+
+	rv := reflect.ValueOf(arg)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			typ, err := ydbTypeOf(rv.Type().Elem())
+			if err != nil {
+				return "", nil, err
+			}
+			return "Optional<" + typ + ">", nil, nil
+		}
+		return ydbType(rv.Elem().Interface())
+	}
+
+	if valuer, ok := arg.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return "", nil, err
+		}
+		if v == nil {
+			typ, err := ydbTypeOf(rv.Type())
+			if err != nil {
+				return "", nil, err
+			}
+			return "Optional<" + typ + ">", nil, nil
+		}
+		return ydbType(v)
+	}
+
+	typ, err := ydbTypeOf(rv.Type())
+	if err != nil {
+		return "", nil, err
+	}
+	// The driver checks the values against the declared types, so integers
+	// are passed with the width of their declaration.
+	if t, ok := ydbIntTypes[rv.Kind()]; ok && rv.Type() != ydbDurationType {
+		return typ, rv.Convert(t).Interface(), nil
+	}
+	return typ, arg, nil
+}
+
+// ydbTypeOf maps a Go type to a YQL type. Nullable types implementing
+// `driver.Valuer`, such as `nulls.String` or `sql.NullInt64`, are mapped to the
+// type of their value field.
+func ydbTypeOf(t reflect.Type) (string, error) {
+	switch t {
+	case ydbTimeType:
+		return "Timestamp", nil
+	case ydbDurationType:
+		return "Interval", nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "Bool", nil
+	case reflect.Int, reflect.Int64:
+		return "Int64", nil
+	case reflect.Int32:
+		return "Int32", nil
+	case reflect.Int16:
+		return "Int16", nil
+	case reflect.Int8:
+		return "Int8", nil
+	case reflect.Uint, reflect.Uint64:
+		return "Uint64", nil
+	case reflect.Uint32:
+		return "Uint32", nil
+	case reflect.Uint16:
+		return "Uint16", nil
+	case reflect.Uint8:
+		return "Uint8", nil
+	case reflect.Float32:
+		return "Float", nil
+	case reflect.Float64:
+		return "Double", nil
+	case reflect.String:
+		return "Utf8", nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "String", nil
+		}
+	case reflect.Array:
+		// uuid.UUID and friends are sent as their string representation.
+		if t.Implements(ydbValuerType) {
+			return "Utf8", nil
+		}
+	case reflect.Struct:
+		if t.Implements(ydbValuerType) {
+			for i := 0; i < t.NumField(); i++ {
+				if f := t.Field(i); f.Name != "Valid" && f.PkgPath == "" {
+					return ydbTypeOf(f.Type)
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+// ydbStore binds the arguments of every statement for YQL before passing it
+// to the underlying store.
+type ydbStore struct {
+	Store
+	dialect *ydb
+}
+
+func (s ydbStore) Select(dest interface{}, query string, args ...interface{}) error {
+	return s.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (s ydbStore) Get(dest interface{}, query string, args ...interface{}) error {
+	return s.GetContext(context.Background(), dest, query, args...)
+}
+
+func (s ydbStore) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.ExecContext(context.Background(), query, args...)
+}
+
+func (s ydbStore) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return s.NamedExecContext(context.Background(), query, arg)
+}
+
+func (s ydbStore) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	return s.NamedQueryContext(context.Background(), query, arg)
+}
+
+func (s ydbStore) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
+	return s.QueryxContext(context.Background(), query, args...)
+}
+
+func (s ydbStore) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	query, args, err := ydbBind(query, args)
+	if err != nil {
+		return err
+	}
+	return s.Store.SelectContext(ctx, dest, query, args...)
+}
+
+func (s ydbStore) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	query, args, err := ydbBind(query, args)
+	if err != nil {
+		return err
+	}
+	return s.Store.GetContext(ctx, dest, query, args...)
+}
+
+func (s ydbStore) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	query, args, err := ydbBind(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.Store.ExecContext(ctx, query, args...)
+}
+
+func (s ydbStore) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return s.ExecContext(ctx, s.dialect.TranslateSQL(query), args...)
+}
+
+func (s ydbStore) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return s.QueryxContext(ctx, s.dialect.TranslateSQL(query), args...)
+}
+
+func (s ydbStore) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	query, args, err := ydbBind(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.Store.QueryxContext(ctx, query, args...)
+}
@@ -0,0 +1,368 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/fizz"
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const ydbFakeDriverName = "ydb_fake"
+
+func init() {
+	sql.Register(ydbFakeDriverName, ydbFake)
+}
+
+// ydbFake is an in-process stand-in for the YDB database/sql driver which
+// records the statements and the named arguments it receives.
+var ydbFake = &ydbFakeDriver{}
+
+type ydbFakeStatement struct {
+	Query string
+	Args  []driver.NamedValue
+}
+
+type ydbFakeDriver struct {
+	mu         sync.Mutex
+	statements []ydbFakeStatement
+}
+
+func (d *ydbFakeDriver) Open(string) (driver.Conn, error) {
+	return &ydbFakeConn{driver: d}, nil
+}
+
+func (d *ydbFakeDriver) record(query string, args []driver.NamedValue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, ydbFakeStatement{Query: query, Args: args})
+}
+
+func (d *ydbFakeDriver) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = nil
+}
+
+func (d *ydbFakeDriver) last() ydbFakeStatement {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.statements) == 0 {
+		return ydbFakeStatement{}
+	}
+	return d.statements[len(d.statements)-1]
+}
+
+type ydbFakeConn struct {
+	driver *ydbFakeDriver
+}
+
+func (c *ydbFakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *ydbFakeConn) Close() error {
+	return nil
+}
+
+func (c *ydbFakeConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *ydbFakeConn) Commit() error {
+	c.driver.record("COMMIT", nil)
+	return nil
+}
+
+func (c *ydbFakeConn) Rollback() error {
+	c.driver.record("ROLLBACK", nil)
+	return nil
+}
+
+func (c *ydbFakeConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *ydbFakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.driver.record(query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *ydbFakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.driver.record(query, args)
+	return ydbFakeRows{}, nil
+}
+
+type ydbFakeRows struct{}
+
+func (ydbFakeRows) Columns() []string              { return []string{} }
+func (ydbFakeRows) Close() error                   { return nil }
+func (ydbFakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+func newYDBFakeConnection(t *testing.T) *Connection {
+	r := require.New(t)
+	ydbFake.reset()
+
+	c, err := NewConnection(&ConnectionDetails{
+		Dialect:  "ydb",
+		Driver:   ydbFakeDriverName,
+		Database: "local",
+	})
+	r.NoError(err)
+	r.NoError(c.Open())
+	return c
+}
+
+func Test_YDB_ConnectionDetails_Values_Finalize(t *testing.T) {
+	r := require.New(t)
+
+	cd := &ConnectionDetails{
+		Dialect:  "ydb",
+		Database: "local",
+		Driver:   ydbFakeDriverName,
+	}
+	r.NoError(cd.Finalize())
+	r.Equal("localhost", cd.Host)
+	r.Equal("2136", cd.Port)
+
+	d, err := newYDB(cd)
+	r.NoError(err)
+	r.Equal("grpc://localhost:2136/local", d.URL())
+
+	cd.setOption("secure", "true")
+	r.Equal("grpcs://localhost:2136/local?secure=true", d.URL())
+}
+
+func Test_YDB_ConnectionDetails_URL_Finalize(t *testing.T) {
+	r := require.New(t)
+
+	cd := &ConnectionDetails{
+		Dialect: "ydb",
+		URL:     "grpcs://ydb.example.com:2135/ru-central1/b1g/etn?go_query_mode=scripting",
+	}
+	r.NoError(cd.Finalize())
+	r.Equal("ydb.example.com", cd.Host)
+	r.Equal("2135", cd.Port)
+	r.Equal("ru-central1/b1g/etn", cd.Database)
+	r.Equal("true", cd.option("secure"))
+	r.Equal("scripting", cd.option("go_query_mode"))
+
+	cd = &ConnectionDetails{
+		Dialect: "ydb",
+		URL:     "ydb://localhost:2136/local",
+		Driver:  ydbFakeDriverName,
+	}
+	r.NoError(cd.Finalize())
+	d, err := newYDB(cd)
+	r.NoError(err)
+	r.Equal("grpc://localhost:2136/local", d.URL())
+
+	// the dialect is guessed from grpc(s) URLs.
+	cd = &ConnectionDetails{URL: "grpc://localhost:2136/local"}
+	r.NoError(cd.Finalize())
+	r.Equal("ydb", cd.Dialect)
+	r.Equal("local", cd.Database)
+}
+
+func Test_YDB_MissingDriver(t *testing.T) {
+	r := require.New(t)
+
+	_, err := NewConnection(&ConnectionDetails{
+		Dialect:  "ydb",
+		Driver:   "ydb_missing",
+		Database: "local",
+	})
+	r.Error(err)
+}
+
+func Test_YDB_Quote(t *testing.T) {
+	r := require.New(t)
+
+	d := &ydb{}
+	r.Equal("`users`", d.Quote("users"))
+	r.Equal("`users`.`id`", d.Quote("users.id"))
+	r.Equal("`users`", d.Quote("`users`"))
+}
+
+func Test_YDB_TranslateSQL(t *testing.T) {
+	r := require.New(t)
+
+	d := &ydb{translateCache: map[string]string{}}
+	r.Equal("SELECT * FROM `t` WHERE a = $p1 AND b IN ($p2, $p3)", d.TranslateSQL("SELECT * FROM `t` WHERE a = ? AND b IN (?, ?)"))
+	r.Equal("SELECT '?' AS q, `a?` FROM t WHERE x = $p1", d.TranslateSQL("SELECT '?' AS q, `a?` FROM t WHERE x = ?"))
+	r.Equal("SELECT 1", d.TranslateSQL("SELECT 1"))
+	r.Equal("SELECT 'it\\'s?' FROM t WHERE x = $p1", d.TranslateSQL("SELECT 'it\\'s?' FROM t WHERE x = ?"))
+	r.Equal("SELECT a -- b?\nFROM t /* c? */ WHERE x = $p1", d.TranslateSQL("SELECT a -- b?\nFROM t /* c? */ WHERE x = ?"))
+	r.Equal("SELECT 1 -- ?", d.TranslateSQL("SELECT 1 -- ?"))
+	r.Equal("SELECT 1 /* ?", d.TranslateSQL("SELECT 1 /* ?"))
+}
+
+func Test_YDB_Bind(t *testing.T) {
+	r := require.New(t)
+
+	now := time.Now()
+	id := uuid.Must(uuid.NewV4())
+	q, args, err := ydbBind("SELECT $p1, $p2, $p3, $p4, $p5, $p6, $p7", []interface{}{
+		1, "a", true, now, id, nulls.String{}, nulls.NewInt(2),
+	})
+	r.NoError(err)
+	r.Equal(strings.Join([]string{
+		"DECLARE $p1 AS Int64;",
+		"DECLARE $p2 AS Utf8;",
+		"DECLARE $p3 AS Bool;",
+		"DECLARE $p4 AS Timestamp;",
+		"DECLARE $p5 AS Utf8;",
+		"DECLARE $p6 AS Optional<Utf8>;",
+		"DECLARE $p7 AS Int64;",
+		"SELECT $p1, $p2, $p3, $p4, $p5, $p6, $p7",
+	}, "\n"), q)
+	r.Equal([]interface{}{
+		sql.Named("p1", int64(1)),
+		sql.Named("p2", "a"),
+		sql.Named("p3", true),
+		sql.Named("p4", now),
+		sql.Named("p5", id.String()),
+		sql.Named("p6", nil),
+		sql.Named("p7", int64(2)),
+	}, args)
+
+	type level uint8
+	q, args, err = ydbBind("SELECT $p1, $p2, $p3", []interface{}{int32(3), uint8(4), level(5)})
+	r.NoError(err)
+	r.Equal("DECLARE $p1 AS Int32;\nDECLARE $p2 AS Uint8;\nDECLARE $p3 AS Uint8;\nSELECT $p1, $p2, $p3", q)
+	r.Equal([]interface{}{
+		sql.Named("p1", int32(3)),
+		sql.Named("p2", uint8(4)),
+		sql.Named("p3", uint8(5)),
+	}, args)
+
+	named := []interface{}{sql.Named("name", "a")}
+	q, args, err = ydbBind("SELECT $name", named)
+	r.NoError(err)
+	r.Equal("SELECT $name", q)
+	r.Equal(named, args)
+
+	_, _, err = ydbBind("SELECT $p1", []interface{}{nil})
+	r.Error(err)
+}
+
+func Test_YDB_Create(t *testing.T) {
+	r := require.New(t)
+	c := newYDBFakeConnection(t)
+
+	course := &Course{}
+	r.NoError(c.Create(course))
+	r.NotEqual(uuid.Nil, course.ID)
+
+	stmt := ydbFake.last()
+	r.Contains(stmt.Query, "DECLARE $p1 AS Timestamp;")
+	r.Contains(stmt.Query, "DECLARE $p2 AS Utf8;")
+	r.True(strings.HasSuffix(stmt.Query, "INSERT INTO `courses` (`created_at`, `id`, `updated_at`) VALUES ($p1, $p2, $p3)"), stmt.Query)
+	r.Len(stmt.Args, 3)
+	r.Equal("p2", stmt.Args[1].Name)
+	r.Equal(course.ID.String(), stmt.Args[1].Value)
+
+	r.Error(c.Create(&Composer{Name: "no id"}))
+	r.NoError(c.Create(&Composer{ID: 1, Name: "Bach"}))
+	r.Contains(ydbFake.last().Query, "INSERT INTO `composers`")
+
+	c.Dialect.Details().setOption("write_mode", "upsert")
+	defer c.Dialect.Details().setOption("write_mode", "")
+	r.NoError(c.Create(&Composer{ID: 2, Name: "Handel"}))
+	r.Contains(ydbFake.last().Query, "UPSERT INTO `composers`")
+}
+
+type ydbCounter struct {
+	ID    int   `db:"id"`
+	Hits  int32 `db:"hits"`
+	Level uint8 `db:"level"`
+}
+
+func (ydbCounter) TableName() string { return "ydb_counters" }
+
+func Test_YDB_Create_Widths(t *testing.T) {
+	r := require.New(t)
+	c := newYDBFakeConnection(t)
+
+	r.NoError(c.Create(&ydbCounter{ID: 1, Hits: 10, Level: 2}))
+	stmt := ydbFake.last()
+	r.Equal("DECLARE $p1 AS Int32;\nDECLARE $p2 AS Int64;\nDECLARE $p3 AS Uint8;\nINSERT INTO `ydb_counters` (`hits`, `id`, `level`) VALUES ($p1, $p2, $p3)", stmt.Query)
+	r.Equal(int32(10), stmt.Args[0].Value)
+	r.Equal(int64(1), stmt.Args[1].Value)
+	r.Equal(uint8(2), stmt.Args[2].Value)
+}
+
+func Test_YDB_Update_Destroy(t *testing.T) {
+	r := require.New(t)
+	c := newYDBFakeConnection(t)
+
+	composer := &Composer{ID: 1, Name: "Bach"}
+	r.NoError(c.Update(composer))
+	r.True(strings.HasSuffix(ydbFake.last().Query, "UPDATE `composers` SET `name` = $p1, `updated_at` = $p2 WHERE `id` = $p3"), ydbFake.last().Query)
+
+	r.NoError(c.Destroy(composer))
+	stmt := ydbFake.last()
+	r.Equal("DECLARE $p1 AS Int64;\nDELETE FROM `composers` WHERE `id` = $p1", stmt.Query)
+	r.Equal(int64(1), stmt.Args[0].Value)
+
+	r.NoError(c.Where("name = ?", "Bach").Delete(&Composer{}))
+	r.Equal("DECLARE $p1 AS Utf8;\nDELETE FROM composers WHERE name = $p1", ydbFake.last().Query)
+}
+
+func Test_YDB_Select_Transaction(t *testing.T) {
+	r := require.New(t)
+	c := newYDBFakeConnection(t)
+
+	err := c.Transaction(func(tx *Connection) error {
+		composers := []Composer{}
+		if err := tx.Where("name = ?", "Bach").Limit(2).All(&composers); err != nil {
+			return err
+		}
+		r.Equal("DECLARE $p1 AS Utf8;\nSELECT composers.created_at, composers.id, composers.name, composers.updated_at FROM composers AS composers WHERE name = $p1 LIMIT 2", ydbFake.last().Query)
+
+		r.Error(tx.Transaction(func(tx *Connection) error { return nil }))
+		return nil
+	})
+	r.NoError(err)
+	r.Equal("COMMIT", ydbFake.last().Query)
+}
+
+func Test_YDB_FizzTranslator(t *testing.T) {
+	r := require.New(t)
+
+	table := fizz.NewTable("users", nil)
+	r.NoError(table.Column("id", "uuid", fizz.Options{"primary": true}))
+	r.NoError(table.Column("email", "string", fizz.Options{}))
+	r.NoError(table.Column("age", "integer", fizz.Options{"null": true}))
+	r.NoError(table.Index("email", fizz.Options{"unique": true}))
+	r.NoError(table.Timestamps())
+
+	tr := (&ydb{}).FizzTranslator()
+	res, err := tr.CreateTable(table)
+	r.NoError(err)
+	r.Equal("CREATE TABLE `users` (\n"+
+		"`id` Utf8 NOT NULL,\n"+
+		"`email` Utf8,\n"+
+		"`age` Int64,\n"+
+		"`created_at` Timestamp,\n"+
+		"`updated_at` Timestamp,\n"+
+		"INDEX `users_email_idx` GLOBAL UNIQUE ON (`email`),\n"+
+		"PRIMARY KEY (`id`)\n"+
+		");", res)
+
+	res, err = tr.CreateTable(newSchemaMigrations("schema_migration"))
+	r.NoError(err)
+	r.NotContains(res, "INDEX")
+
+	_, err = tr.AddForeignKey(table)
+	r.Error(err)
+}
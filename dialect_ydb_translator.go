@@ -0,0 +1,168 @@
+package pop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gobuffalo/fizz"
+	"github.com/gobuffalo/pop/v6/logging"
+)
+
+var _ fizz.Translator = &ydbTranslator{}
+
+// ydbTranslator translates fizz migrations to YQL. YDB tables have no
+// foreign keys, no column defaults and can not change the type of an
+// existing column, so these operations are rejected or skipped.
+type ydbTranslator struct{}
+
+func (ydbTranslator) Name() string {
+	return nameYDB
+}
+
+func (p *ydbTranslator) CreateTable(t fizz.Table) (string, error) {
+	cols := []string{}
+	for _, c := range t.Columns {
+		cols = append(cols, p.buildColumn(c))
+	}
+
+	primaryKeys := t.PrimaryKeys()
+	if len(primaryKeys) == 0 {
+		return "", fmt.Errorf("table %s must have a primary key in YDB", t.Name)
+	}
+
+	for _, i := range t.Indexes {
+		// the primary key is already unique and indexed.
+		if i.Unique && strings.Join(i.Columns, ",") == strings.Join(primaryKeys, ",") {
+			continue
+		}
+		cols = append(cols, p.buildIndex(i))
+	}
+
+	if len(t.ForeignKeys) > 0 {
+		log(logging.Warn, "YDB does not support foreign keys, skipping them for table %s", t.Name)
+	}
+
+	cols = append(cols, fmt.Sprintf("PRIMARY KEY (%s)", p.quoteAll(primaryKeys)))
+
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n);", p.quote(t.Name), strings.Join(cols, ",\n")), nil
+}
+
+func (p *ydbTranslator) DropTable(t fizz.Table) (string, error) {
+	return fmt.Sprintf("DROP TABLE %s;", p.quote(t.Name)), nil
+}
+
+func (p *ydbTranslator) RenameTable(t []fizz.Table) (string, error) {
+	if len(t) < 2 {
+		return "", fmt.Errorf("not enough table names supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", p.quote(t[0].Name), p.quote(t[1].Name)), nil
+}
+
+func (p *ydbTranslator) AddColumn(t fizz.Table) (string, error) {
+	if len(t.Columns) == 0 {
+		return "", fmt.Errorf("not enough columns supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", p.quote(t.Name), p.buildColumn(t.Columns[0])), nil
+}
+
+func (p *ydbTranslator) ChangeColumn(t fizz.Table) (string, error) {
+	return "", errors.New("changing a column is not supported by YDB")
+}
+
+func (p *ydbTranslator) DropColumn(t fizz.Table) (string, error) {
+	if len(t.Columns) == 0 {
+		return "", fmt.Errorf("not enough columns supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", p.quote(t.Name), p.quote(t.Columns[0].Name)), nil
+}
+
+func (p *ydbTranslator) RenameColumn(t fizz.Table) (string, error) {
+	return "", errors.New("renaming a column is not supported by YDB")
+}
+
+func (p *ydbTranslator) AddIndex(t fizz.Table) (string, error) {
+	if len(t.Indexes) == 0 {
+		return "", fmt.Errorf("not enough indexes supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", p.quote(t.Name), p.buildIndex(t.Indexes[0])), nil
+}
+
+func (p *ydbTranslator) DropIndex(t fizz.Table) (string, error) {
+	if len(t.Indexes) == 0 {
+		return "", fmt.Errorf("not enough indexes supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s DROP INDEX %s;", p.quote(t.Name), p.quote(t.Indexes[0].Name)), nil
+}
+
+func (p *ydbTranslator) RenameIndex(t fizz.Table) (string, error) {
+	if len(t.Indexes) < 2 {
+		return "", fmt.Errorf("not enough indexes supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME INDEX %s TO %s;", p.quote(t.Name), p.quote(t.Indexes[0].Name), p.quote(t.Indexes[1].Name)), nil
+}
+
+func (p *ydbTranslator) AddForeignKey(t fizz.Table) (string, error) {
+	return "", errors.New("foreign keys are not supported by YDB")
+}
+
+func (p *ydbTranslator) DropForeignKey(t fizz.Table) (string, error) {
+	return "", errors.New("foreign keys are not supported by YDB")
+}
+
+func (p *ydbTranslator) buildColumn(c fizz.Column) string {
+	s := fmt.Sprintf("%s %s", p.quote(c.Name), p.colType(c))
+	if c.Primary {
+		s = fmt.Sprintf("%s NOT NULL", s)
+	}
+	return s
+}
+
+func (p *ydbTranslator) buildIndex(i fizz.Index) string {
+	kind := "GLOBAL"
+	if i.Unique {
+		kind = "GLOBAL UNIQUE"
+	}
+	return fmt.Sprintf("INDEX %s %s ON (%s)", p.quote(i.Name), kind, p.quoteAll(i.Columns))
+}
+
+func (p *ydbTranslator) colType(c fizz.Column) string {
+	switch strings.ToLower(c.ColType) {
+	case "string", "text", "varchar", "uuid":
+		return "Utf8"
+	case "int", "integer", "bigint":
+		return "Int64"
+	case "smallint":
+		return "Int32"
+	case "bool", "boolean":
+		return "Bool"
+	case "timestamp", "time", "datetime":
+		return "Timestamp"
+	case "date":
+		return "Date"
+	case "float", "double":
+		return "Double"
+	case "decimal", "numeric":
+		return "Decimal(22,9)"
+	case "json":
+		return "Json"
+	case "jsonb":
+		return "JsonDocument"
+	case "blob", "[]byte":
+		return "String"
+	default:
+		return c.ColType
+	}
+}
+
+func (p *ydbTranslator) quote(s string) string {
+	return fmt.Sprintf("`%s`", s)
+}
+
+func (p *ydbTranslator) quoteAll(xs []string) string {
+	quoted := make([]string, len(xs))
+	for i, x := range xs {
+		quoted[i] = p.quote(x)
+	}
+	return strings.Join(quoted, ", ")
+}
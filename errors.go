@@ -0,0 +1,140 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// ErrNotFound is returned when no record matches a query for a single
+// record, such as Find or First. It wraps sql.ErrNoRows, so errors.Is also
+// matches sql.ErrNoRows.
+var ErrNotFound = errors.New("record not found")
+
+// ErrDeadlock is returned when the database aborted a statement to resolve
+// a deadlock. The transaction can be retried, see TransactionWithRetry.
+var ErrDeadlock = errors.New("deadlock detected")
+
+// ErrSerialization is returned when the database aborted a statement which
+// could not be serialized with concurrent transactions. The transaction can
+// be retried, see TransactionWithRetry.
+var ErrSerialization = errors.New("serialization failure")
+
+// ErrTimeout is returned when a statement was canceled because it took
+// longer than its timeout, set by Query.Timeout or the statement_timeout
+// option of the connection, or than the deadline of its context.
+var ErrTimeout = errors.New("statement timeout")
+
+// ErrUniqueViolation is returned when a statement violates a unique
+// constraint or index.
+type ErrUniqueViolation struct {
+	// Constraint is the name of the constraint or index, if the database
+	// reports it.
+	Constraint string
+	// Columns of the constraint, if the database reports them.
+	Columns []string
+	// Err is the error of the driver.
+	Err error
+}
+
+func (e *ErrUniqueViolation) Error() string { return e.Err.Error() }
+func (e *ErrUniqueViolation) Unwrap() error { return e.Err }
+
+// ErrForeignKeyViolation is returned when a statement violates a foreign key
+// constraint.
+type ErrForeignKeyViolation struct {
+	// Constraint is the name of the constraint, if the database reports it.
+	Constraint string
+	// Err is the error of the driver.
+	Err error
+}
+
+func (e *ErrForeignKeyViolation) Error() string { return e.Err.Error() }
+func (e *ErrForeignKeyViolation) Unwrap() error { return e.Err }
+
+// ErrNotNullViolation is returned when a statement sets NULL in a NOT NULL
+// column.
+type ErrNotNullViolation struct {
+	// Column is the name of the column, if the database reports it.
+	Column string
+	// Err is the error of the driver.
+	Err error
+}
+
+func (e *ErrNotNullViolation) Error() string { return e.Err.Error() }
+func (e *ErrNotNullViolation) Unwrap() error { return e.Err }
+
+// ErrCheckViolation is returned when a statement violates a check
+// constraint.
+type ErrCheckViolation struct {
+	// Constraint is the name of the constraint, if the database reports it.
+	Constraint string
+	// Err is the error of the driver.
+	Err error
+}
+
+func (e *ErrCheckViolation) Error() string { return e.Err.Error() }
+func (e *ErrCheckViolation) Unwrap() error { return e.Err }
+
+// wrappedError wraps the error of the driver, so errors.Is also matches the
+// sentinel error of pop for it.
+type wrappedError struct {
+	sentinel error
+	err      error
+}
+
+func (e *wrappedError) Error() string        { return e.err.Error() }
+func (e *wrappedError) Unwrap() error        { return e.err }
+func (e *wrappedError) Is(target error) bool { return target == e.sentinel }
+
+// timeoutError maps the error of a statement run with the context to
+// ErrTimeout, if the deadline of the context was exceeded.
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, ErrTimeout) {
+		return err
+	}
+	return &wrappedError{sentinel: ErrTimeout, err: err}
+}
+
+// translateError maps an error of the driver, returned by a crudable method
+// of the dialect, to the typed errors of pop.
+func (c *Connection) translateError(err error) error {
+	if err == nil || isTranslated(err) {
+		return err
+	}
+	if d, ok := c.Dialect.(errorTranslator); ok {
+		return d.TranslateError(err)
+	}
+	return err
+}
+
+// notFoundError maps sql.ErrNoRows to ErrNotFound, and the other errors like
+// translateError.
+func (c *Connection) notFoundError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) && !errors.Is(err, ErrNotFound) {
+		return &wrappedError{sentinel: ErrNotFound, err: err}
+	}
+	return c.translateError(err)
+}
+
+// isTranslated tells if the error was already mapped to a typed error.
+func isTranslated(err error) bool {
+	var w *wrappedError
+	var uv *ErrUniqueViolation
+	var fk *ErrForeignKeyViolation
+	var nn *ErrNotNullViolation
+	var cv *ErrCheckViolation
+	return errors.As(err, &w) || errors.As(err, &uv) || errors.As(err, &fk) || errors.As(err, &nn) || errors.As(err, &cv)
+}
+
+// splitColumns splits a list of columns, such as "a, b", dropping the table
+// prefix of the columns.
+func splitColumns(list string) []string {
+	cols := strings.Split(list, ",")
+	for i, col := range cols {
+		col = strings.TrimSpace(col)
+		cols[i] = col[strings.LastIndex(col, ".")+1:]
+	}
+	return cols
+}
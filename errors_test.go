@@ -0,0 +1,110 @@
+package pop
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	_mysql "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TranslatePgError(t *testing.T) {
+	r := require.New(t)
+
+	pgErr := &pgconn.PgError{Code: "23505", ConstraintName: "users_email_name_idx", Detail: "Key (email, name)=(a@b.c, Mark) already exists."}
+	err := translatePgError(fmt.Errorf("insert: %w", pgErr))
+	var uv *ErrUniqueViolation
+	r.True(errors.As(err, &uv))
+	r.Equal("users_email_name_idx", uv.Constraint)
+	r.Equal([]string{"email", "name"}, uv.Columns)
+	r.Equal("insert: "+pgErr.Error(), err.Error())
+	var orig *pgconn.PgError
+	r.True(errors.As(err, &orig))
+
+	var fk *ErrForeignKeyViolation
+	r.True(errors.As(translatePgError(&pgconn.PgError{Code: "23503", ConstraintName: "songs_u_id_fkey"}), &fk))
+	r.Equal("songs_u_id_fkey", fk.Constraint)
+
+	var nn *ErrNotNullViolation
+	r.True(errors.As(translatePgError(&pgconn.PgError{Code: "23502", ColumnName: "name"}), &nn))
+	r.Equal("name", nn.Column)
+
+	var cv *ErrCheckViolation
+	r.True(errors.As(translatePgError(&pgconn.PgError{Code: "23514", ConstraintName: "price_positive"}), &cv))
+	r.Equal("price_positive", cv.Constraint)
+
+	r.ErrorIs(translatePgError(&pgconn.PgError{Code: "40P01"}), ErrDeadlock)
+	r.ErrorIs(translatePgError(&pgconn.PgError{Code: "40001"}), ErrSerialization)
+
+	other := &pgconn.PgError{Code: "42P01"}
+	r.Equal(other, translatePgError(other))
+}
+
+func Test_TranslateMySQLError(t *testing.T) {
+	r := require.New(t)
+
+	var uv *ErrUniqueViolation
+	r.True(errors.As(translateMySQLError(&_mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.users_email_idx'"}), &uv))
+	r.Equal("users_email_idx", uv.Constraint)
+
+	var fk *ErrForeignKeyViolation
+	r.True(errors.As(translateMySQLError(&_mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`pop_test`.`songs`, CONSTRAINT `songs_u_id_fk` FOREIGN KEY (`u_id`) REFERENCES `users` (`id`))"}), &fk))
+	r.Equal("songs_u_id_fk", fk.Constraint)
+
+	var nn *ErrNotNullViolation
+	r.True(errors.As(translateMySQLError(&_mysql.MySQLError{Number: 1048, Message: "Column 'name' cannot be null"}), &nn))
+	r.Equal("name", nn.Column)
+
+	var cv *ErrCheckViolation
+	r.True(errors.As(translateMySQLError(&_mysql.MySQLError{Number: 3819, Message: "Check constraint 'price_positive' is violated."}), &cv))
+	r.Equal("price_positive", cv.Constraint)
+	r.True(errors.As(translateMySQLError(&_mysql.MySQLError{Number: 4025, Message: "CONSTRAINT `price_positive` failed for `pop_test`.`books`"}), &cv))
+	r.Equal("price_positive", cv.Constraint)
+
+	r.ErrorIs(translateMySQLError(&_mysql.MySQLError{Number: 1213}), ErrDeadlock)
+}
+
+func Test_Connection_NotFoundError(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{Dialect: &postgresql{}}
+	err := c.notFoundError(sql.ErrNoRows)
+	r.ErrorIs(err, ErrNotFound)
+	r.ErrorIs(err, sql.ErrNoRows)
+	r.Equal(sql.ErrNoRows.Error(), err.Error())
+	r.Equal(err, c.notFoundError(err))
+
+	uv := c.translateError(&pgconn.PgError{Code: "23505"})
+	r.Equal(uv, c.translateError(uv))
+	r.Nil(c.translateError(nil))
+}
+
+func Test_TypedErrors(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		if _, ok := tx.Dialect.(errorTranslator); !ok {
+			t.Skip("typed errors are not supported by the dialect")
+		}
+
+		err := tx.Find(&Song{}, "00000000-0000-0000-0000-000000000000")
+		r.ErrorIs(err, ErrNotFound)
+		r.ErrorIs(err, sql.ErrNoRows)
+
+		song := Song{Title: "Bohemian Rhapsody"}
+		r.NoError(tx.Create(&song))
+		err = tx.Create(&Song{ID: song.ID, Title: "Killer Queen"})
+		var uv *ErrUniqueViolation
+		r.True(errors.As(err, &uv), "%T %v", err, err)
+		r.Error(uv.Err)
+		if len(uv.Columns) > 0 {
+			r.Equal([]string{"id"}, uv.Columns)
+		}
+	})
+}
@@ -1,8 +1,10 @@
 package pop
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"time"
 
 	"github.com/gobuffalo/pop/v6/associations"
@@ -12,6 +14,11 @@ import (
 	"github.com/gofrs/uuid"
 )
 
+// ErrStaleObject is returned by Update, UpdateColumns and Save when the
+// optimistic locking version of the entry no longer matches the database,
+// because the row was changed or deleted concurrently.
+var ErrStaleObject = errors.New("stale object: the row was changed or deleted concurrently")
+
 // Reload fetch fresh data for a given model, using its ID.
 func (c *Connection) Reload(model interface{}) error {
 	sm := NewModel(model, c.Context())
@@ -30,7 +37,7 @@ func (q *Query) Exec() error {
 			return fmt.Errorf("empty query")
 		}
 
-		txlog(logging.SQL, q.Connection, sql, args...)
+		q.Connection.log(logging.SQL, sql, args...)
 		_, err := q.Connection.Store.Exec(sql, args...)
 		return err
 	})
@@ -46,7 +53,7 @@ func (q *Query) ExecWithCount() (int, error) {
 			return fmt.Errorf("empty query")
 		}
 
-		txlog(logging.SQL, q.Connection, sql, args...)
+		q.Connection.log(logging.SQL, sql, args...)
 		result, err := q.Connection.Store.Exec(sql, args...)
 		if err != nil {
 			return err
@@ -183,6 +190,17 @@ func (c *Connection) ValidateAndCreate(model interface{}, excludeColumns ...stri
 // * Flat (default): Associate existing nested objects only. NO creation or update of nested objects.
 // * Eager: Associate existing nested objects and create non-existent objects. NO change to existing objects.
 func (c *Connection) Create(model interface{}, excludeColumns ...string) error {
+	if c.needsAuditTransaction(model) {
+		eager, eagerFields := c.eager, c.eagerFields
+		c.disableEager()
+		return c.Transaction(func(tx *Connection) error {
+			if eager {
+				tx = tx.Eager(eagerFields...)
+			}
+			return tx.Create(model, excludeColumns...)
+		})
+	}
+
 	var isEager = c.eager
 
 	c.disableEager()
@@ -256,7 +274,7 @@ func (c *Connection) Create(model interface{}, excludeColumns ...string) error {
 			m.setCreatedAt(now)
 
 			if err = c.Dialect.Create(c, m, cols); err != nil {
-				return err
+				return c.translateError(err)
 			}
 
 			if processAssoc {
@@ -316,6 +334,10 @@ func (c *Connection) Create(model interface{}, excludeColumns ...string) error {
 				}
 			}
 
+			m.track()
+			if err = c.audit(m, AuditCreate, nil, false); err != nil {
+				return err
+			}
 			if err = m.afterCreate(c); err != nil {
 				return err
 			}
@@ -325,6 +347,267 @@ func (c *Connection) Create(model interface{}, excludeColumns ...string) error {
 	})
 }
 
+// DefaultBatchSize is the number of rows inserted by a single statement of
+// CreateMany, unless WithBatchSize is given.
+var DefaultBatchSize = 100
+
+// BatchOption configures CreateMany and Each.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	size int
+}
+
+// WithBatchSize sets the maximum number of rows inserted by a single
+// statement of CreateMany, or selected by a single statement of Each. Values
+// lower than 1 are ignored.
+func WithBatchSize(size int) BatchOption {
+	return func(o *batchOptions) {
+		if size > 0 {
+			o.size = size
+		}
+	}
+}
+
+// CreateMany adds all entries of the given slice to the database, using
+// multi-row INSERT statements of up to DefaultBatchSize rows. Generated IDs
+// are set on the entries, and `created_at` and `updated_at` columns are
+// updated automatically.
+//
+// Before and after create/save callbacks run for each entry as with Create.
+// Associations are not created. Wrap the call in a transaction if all batches
+// must succeed or fail together.
+//
+//	c.CreateMany(&users, pop.WithBatchSize(500))
+func (c *Connection) CreateMany(models interface{}, opts ...BatchOption) error {
+	o := batchOptions{size: DefaultBatchSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	sm := NewModel(models, c.Context())
+	if !sm.isSlice() {
+		return fmt.Errorf("CreateMany expects a slice of models, got %T", models)
+	}
+	if c.needsAuditTransaction(models) {
+		return c.Transaction(func(tx *Connection) error {
+			return tx.CreateMany(models, opts...)
+		})
+	}
+
+	return c.timeFunc("CreateMany", func() error {
+		batch := make([]*Model, 0, o.size)
+
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := c.createBatch(batch); err != nil {
+				return err
+			}
+			for _, m := range batch {
+				m.track()
+				if err := c.audit(m, AuditCreate, nil, false); err != nil {
+					return err
+				}
+				if err := m.afterCreate(c); err != nil {
+					return err
+				}
+				if err := m.afterSave(c); err != nil {
+					return err
+				}
+			}
+			batch = batch[:0]
+			return nil
+		}
+
+		err := sm.iterate(func(m *Model) error {
+			if err := m.beforeSave(c); err != nil {
+				return err
+			}
+			if err := m.beforeCreate(c); err != nil {
+				return err
+			}
+
+			now := nowFunc().Truncate(time.Microsecond)
+			m.setUpdatedAt(now)
+			m.setCreatedAt(now)
+
+			batch = append(batch, m)
+			if len(batch) < o.size {
+				return nil
+			}
+			return flush()
+		})
+		if err != nil {
+			return err
+		}
+		return flush()
+	})
+}
+
+// createBatch inserts the batch with a single statement when the dialect
+// supports it, or one by one otherwise.
+func (c *Connection) createBatch(batch []*Model) error {
+	cols := batch[0].Columns()
+	if d, ok := c.Dialect.(batchCreatable); ok {
+		w := cols.Writeable()
+		w.Remove(batch[0].IDField())
+		if len(w.Cols) > 0 {
+			return c.translateError(d.CreateMany(c, batch, cols))
+		}
+	}
+
+	for _, m := range batch {
+		if err := c.Dialect.Create(c, m, m.Columns()); err != nil {
+			return c.translateError(err)
+		}
+	}
+	return nil
+}
+
+// Upsert adds an entry to the database, or updates the existing row when the
+// insert conflicts with a unique constraint on conflictColumns. Only the given
+// updateColumns are overwritten on conflict, or all writeable columns except
+// the conflict columns when none are given. The ID of the inserted or updated
+// row is set on the entry, and `created_at` and `updated_at` columns are
+// handled as with Create and Update: the `created_at` of an existing row is
+// kept and read back into the entry. The optimistic locking version of an
+// existing row is incremented and read back too, and its soft delete column
+// is overwritten with the one of the entry, so it is restored.
+//
+// Upsert runs before and after save callbacks, but no create or update
+// callbacks since the outcome is only known to the database. MySQL and
+// MariaDB ignore conflictColumns and check all unique keys of the table.
+//
+// If model is a slice, each item of the slice is upserted.
+//
+//	c.Upsert(&user, []string{"email"}, "name")
+func (c *Connection) Upsert(model interface{}, conflictColumns []string, updateColumns ...string) error {
+	if len(conflictColumns) == 0 {
+		return errors.New("upsert requires at least one conflict column")
+	}
+	return c.upsert("Upsert", model, conflictColumns, updateColumns, false)
+}
+
+// UpsertIgnore adds an entry to the database unless the insert conflicts with
+// a unique constraint on conflictColumns, or any unique constraint if none are
+// given. The entry is left untouched when the insert is skipped.
+//
+// On MySQL and MariaDB, the statement is an INSERT IGNORE, which also turns
+// other errors, such as invalid values, into warnings.
+func (c *Connection) UpsertIgnore(model interface{}, conflictColumns ...string) error {
+	return c.upsert("UpsertIgnore", model, conflictColumns, nil, true)
+}
+
+func (c *Connection) upsert(name string, model interface{}, conflictColumns, updateColumns []string, ignore bool) error {
+	d, ok := c.Dialect.(upsertable)
+	if !ok {
+		return fmt.Errorf("upsert is not supported by the %s dialect", c.Dialect.Name())
+	}
+
+	if c.needsAuditTransaction(model) {
+		return c.Transaction(func(tx *Connection) error {
+			return tx.upsert(name, model, conflictColumns, updateColumns, ignore)
+		})
+	}
+
+	sm := NewModel(model, c.Context())
+	return sm.iterate(func(m *Model) error {
+		return c.timeFunc(name, func() error {
+			if err := m.beforeSave(c); err != nil {
+				return err
+			}
+
+			cols := m.Columns()
+			opts := UpsertOptions{Conflict: conflictColumns}
+			w := cols.Writeable()
+			if _, ok := w.Cols["created_at"]; ok {
+				opts.Read = append(opts.Read, "created_at")
+			}
+			if !ignore {
+				update, err := upsertUpdateColumns(m, cols, conflictColumns, updateColumns)
+				if err != nil {
+					return err
+				}
+				opts.Update = update
+				if version := m.versionColumn(); version != "" {
+					opts.Version = version
+					opts.Read = append(opts.Read, version)
+				}
+			}
+
+			before, err := c.upsertAuditState(m, conflictColumns)
+			if err != nil {
+				return err
+			}
+
+			now := nowFunc().Truncate(time.Microsecond)
+			m.setUpdatedAt(now)
+			m.setCreatedAt(now)
+
+			if err := d.Upsert(c, m, cols, opts); err != nil {
+				return c.translateError(err)
+			}
+			m.track()
+			// an ignored insert leaves the existing row untouched.
+			switch {
+			case before == nil:
+				err = c.audit(m, AuditCreate, nil, false)
+			case !ignore:
+				err = c.audit(m, AuditUpdate, before, false)
+			}
+			if err != nil {
+				return err
+			}
+			return m.afterSave(c)
+		})
+	})
+}
+
+// upsertUpdateColumns returns the columns overwritten by an upsert on
+// conflict. The ID, `created_at` and the optimistic locking version are never
+// overwritten, while `updated_at` and the soft delete column always are, so
+// a soft deleted row is restored.
+func upsertUpdateColumns(m *Model, cols columns.Columns, conflictColumns, updateColumns []string) ([]string, error) {
+	w := cols.Writeable()
+	if len(updateColumns) == 0 {
+		for _, col := range w.Cols {
+			updateColumns = append(updateColumns, col.Name)
+		}
+		sort.Strings(updateColumns)
+	}
+
+	deleted := m.softDeleteColumn()
+	skip := map[string]bool{m.IDField(): true, "created_at": true, "updated_at": true, m.versionColumn(): true, deleted: true}
+	for _, col := range conflictColumns {
+		skip[col] = true
+	}
+
+	update := []string{}
+	for _, col := range updateColumns {
+		if skip[col] {
+			continue
+		}
+		if _, ok := w.Cols[col]; !ok {
+			return nil, fmt.Errorf("can not update column %s with an upsert", col)
+		}
+		skip[col] = true
+		update = append(update, col)
+	}
+	if _, ok := w.Cols["updated_at"]; ok {
+		update = append(update, "updated_at")
+	}
+	if _, ok := w.Cols[deleted]; ok {
+		update = append(update, deleted)
+	}
+	if len(update) == 0 {
+		// a no-op update still reports the ID of the existing row.
+		update = append(update, conflictColumns[0])
+	}
+	return update, nil
+}
+
 // ValidateAndUpdate applies validation rules on the given entry, then update it
 // if the validation succeed, excluding the given columns.
 //
@@ -347,8 +630,21 @@ func (c *Connection) ValidateAndUpdate(model interface{}, excludeColumns ...stri
 // Update writes changes from an entry to the database, excluding the given columns.
 // It updates the `updated_at` column automatically.
 //
+// Entries with a `lock_version` column, or a field tagged with `version:"true"`,
+// are only updated if the version matches the database, and the version is
+// incremented. ErrStaleObject is returned otherwise.
+//
+// Entries embedding Tracked only have the columns which changed since they
+// were loaded written, and no statement is run if none did.
+//
 // If model is a slice, each item of the slice is updated in the database.
 func (c *Connection) Update(model interface{}, excludeColumns ...string) error {
+	if c.needsAuditTransaction(model) {
+		return c.Transaction(func(tx *Connection) error {
+			return tx.Update(model, excludeColumns...)
+		})
+	}
+
 	sm := NewModel(model, c.Context())
 	return sm.iterate(func(m *Model) error {
 		return c.timeFunc("Update", func() error {
@@ -368,12 +664,27 @@ func (c *Connection) Update(model interface{}, excludeColumns ...string) error {
 			if tn == sm.TableName() {
 				cols.Remove(excludeColumns...)
 			}
+			if version := m.versionColumn(); version != "" {
+				cols.Add(version)
+			}
 
-			now := nowFunc().Truncate(time.Microsecond)
-			m.setUpdatedAt(now)
+			cols, tracked := m.changedColumns(cols)
+			if !tracked || len(cols.Cols) > 0 {
+				before, err := c.auditState(m)
+				if err != nil {
+					return err
+				}
 
-			if err = c.Dialect.Update(c, m, cols); err != nil {
-				return err
+				now := nowFunc().Truncate(time.Microsecond)
+				m.setUpdatedAt(now)
+
+				if err = c.Dialect.Update(c, m, cols); err != nil {
+					return c.translateError(err)
+				}
+				m.track(cols)
+				if err = c.audit(m, AuditUpdate, before, false); err != nil {
+					return err
+				}
 			}
 			if err = m.afterUpdate(c); err != nil {
 				return err
@@ -401,6 +712,18 @@ func (q *Query) UpdateQuery(model interface{}, columnNames ...string) (int64, er
 		return 0, fmt.Errorf("model must be a struct; got %s", modelKind)
 	}
 
+	if q.Connection.needsAuditTransaction(model) {
+		var n int64
+		err := q.Connection.Transaction(func(tx *Connection) error {
+			tq := Q(tx)
+			q.Clone(tq)
+			var err error
+			n, err = tq.UpdateQuery(model, columnNames...)
+			return err
+		})
+		return n, err
+	}
+
 	cols := columns.NewColumnsWithAlias(sm.TableName(), sm.As, sm.IDField())
 	cols.Add(columnNames...)
 	if _, err := sm.fieldByName("UpdatedAt"); err == nil {
@@ -408,17 +731,42 @@ func (q *Query) UpdateQuery(model interface{}, columnNames ...string) (int64, er
 	}
 	cols.Remove(sm.IDField(), "created_at")
 
+	rows, before, err := q.auditRows(sm)
+	if err != nil {
+		return 0, err
+	}
+
 	now := nowFunc().Truncate(time.Microsecond)
 	sm.setUpdatedAt(now)
-	return q.Connection.Dialect.UpdateQuery(q.Connection, sm, cols, *q)
+	n, err := q.Connection.Dialect.UpdateQuery(q.Connection, sm, cols, *q)
+	if err != nil {
+		return n, q.Connection.translateError(err)
+	}
+	for i, row := range rows {
+		after, err := q.Connection.auditState(row)
+		if err != nil {
+			return n, err
+		}
+		if err := q.Connection.writeAudit(row, AuditUpdate, before[i], after); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
 }
 
 // UpdateColumns writes changes from an entry to the database, including only the given columns
 // or all columns if no column names are provided.
 // It updates the `updated_at` column automatically if you include `updated_at` in columnNames.
+// The optimistic locking version is checked and incremented as with Update.
 //
 // If model is a slice, each item of the slice is updated in the database.
 func (c *Connection) UpdateColumns(model interface{}, columnNames ...string) error {
+	if c.needsAuditTransaction(model) {
+		return c.Transaction(func(tx *Connection) error {
+			return tx.UpdateColumns(model, columnNames...)
+		})
+	}
+
 	sm := NewModel(model, c.Context())
 	return sm.iterate(func(m *Model) error {
 		return c.timeFunc("Update", func() error {
@@ -442,11 +790,23 @@ func (c *Connection) UpdateColumns(model interface{}, columnNames ...string) err
 				cols = columns.ForStructWithAlias(model, tn, m.As, m.IDField())
 			}
 			cols.Remove("id", "created_at")
+			if version := m.versionColumn(); version != "" {
+				cols.Add(version)
+			}
+
+			before, err := c.auditState(m)
+			if err != nil {
+				return err
+			}
 
 			now := nowFunc().Truncate(time.Microsecond)
 			m.setUpdatedAt(now)
 
 			if err = c.Dialect.Update(c, m, cols); err != nil {
+				return c.translateError(err)
+			}
+			m.track(cols)
+			if err = c.audit(m, AuditUpdate, before, false); err != nil {
 				return err
 			}
 			if err = m.afterUpdate(c); err != nil {
@@ -458,19 +818,52 @@ func (c *Connection) UpdateColumns(model interface{}, columnNames ...string) err
 	})
 }
 
-// Destroy deletes a given entry from the database.
+// Destroy deletes a given entry from the database. Entries with a soft
+// delete column, such as `DeletedAt`, are marked as deleted instead.
 //
 // If model is a slice, each item of the slice is deleted from the database.
 func (c *Connection) Destroy(model interface{}) error {
+	return c.destroy("Destroy", model, false)
+}
+
+// HardDestroy deletes a given entry from the database, even if it has a soft
+// delete column.
+//
+// If model is a slice, each item of the slice is deleted from the database.
+func (c *Connection) HardDestroy(model interface{}) error {
+	return c.destroy("HardDestroy", model, true)
+}
+
+func (c *Connection) destroy(name string, model interface{}, hard bool) error {
+	if c.needsAuditTransaction(model) {
+		return c.Transaction(func(tx *Connection) error {
+			return tx.destroy(name, model, hard)
+		})
+	}
+
 	sm := NewModel(model, c.Context())
 	return sm.iterate(func(m *Model) error {
-		return c.timeFunc("Destroy", func() error {
+		return c.timeFunc(name, func() error {
 			var err error
 
 			if err = m.beforeDestroy(c); err != nil {
 				return err
 			}
-			if err = c.Dialect.Destroy(c, m); err != nil {
+			before, err := c.auditState(m)
+			if err != nil {
+				return err
+			}
+			col := m.softDeleteColumn()
+			soft := col != "" && !hard
+			if soft {
+				err = c.setDeleted(m, col, nowFunc().Truncate(time.Microsecond))
+			} else {
+				err = c.translateError(c.Dialect.Destroy(c, m))
+			}
+			if err != nil {
+				return err
+			}
+			if err = c.audit(m, AuditDestroy, before, !soft); err != nil {
 				return err
 			}
 
@@ -479,15 +872,108 @@ func (c *Connection) Destroy(model interface{}) error {
 	})
 }
 
+// Restore clears the soft delete column of a given entry, so it is found by
+// queries again.
+//
+// If model is a slice, each item of the slice is restored.
+func (c *Connection) Restore(model interface{}) error {
+	if c.needsAuditTransaction(model) {
+		return c.Transaction(func(tx *Connection) error {
+			return tx.Restore(model)
+		})
+	}
+
+	sm := NewModel(model, c.Context())
+	return sm.iterate(func(m *Model) error {
+		return c.timeFunc("Restore", func() error {
+			col := m.softDeleteColumn()
+			if col == "" {
+				return fmt.Errorf("%s has no soft delete column", m.TableName())
+			}
+			before, err := c.auditState(m)
+			if err != nil {
+				return err
+			}
+			if err := c.setDeleted(m, col, time.Time{}); err != nil {
+				return err
+			}
+			return c.audit(m, AuditUpdate, before, false)
+		})
+	})
+}
+
+// setDeleted writes the soft delete column of the model, which is cleared
+// for the zero time.
+func (c *Connection) setDeleted(m *Model, col string, t time.Time) error {
+	if err := m.setDeletedAt(t); err != nil {
+		return err
+	}
+	cols := columns.NewColumnsWithAlias(m.TableName(), m.As, m.IDField())
+	cols.Add(col)
+	return c.translateError(c.Dialect.Update(c, m, cols))
+}
+
+// Delete deletes all rows matched by the query. Rows of models with a soft
+// delete column are marked as deleted instead, unless the query is Unscoped
+// or OnlyDeleted.
 func (q *Query) Delete(model interface{}) error {
+	if q.Connection.needsAuditTransaction(model) {
+		return q.Connection.Transaction(func(tx *Connection) error {
+			tq := Q(tx)
+			q.Clone(tq)
+			return tq.Delete(model)
+		})
+	}
+
 	q.Operation = Delete
 
 	return q.Connection.timeFunc("Delete", func() error {
 		m := NewModel(model, q.Connection.Context())
-		err := q.Connection.Dialect.Delete(q.Connection, m, *q)
+		rows, before, err := q.auditRows(m)
+		if err != nil {
+			return err
+		}
+
+		now := nowFunc().Truncate(time.Microsecond)
+		col := m.softDeleteColumn()
+		soft := col != "" && !q.unscoped && !q.onlyDeleted
+		if soft {
+			err = q.softDelete(m, col, now)
+		} else {
+			err = q.Connection.translateError(q.Connection.Dialect.Delete(q.Connection, m, *q))
+		}
 		if err != nil {
 			return err
 		}
+		for i, row := range rows {
+			if soft {
+				if err := row.setDeletedAt(now); err != nil {
+					return err
+				}
+			}
+			if err := q.Connection.audit(row, AuditDestroy, before[i], !soft); err != nil {
+				return err
+			}
+		}
 		return m.afterDestroy(q.Connection)
 	})
 }
+
+// softDelete marks all rows matched by the query as deleted at the given
+// time.
+func (q *Query) softDelete(m *Model, col string, t time.Time) error {
+	mt := reflect.TypeOf(m.Value)
+	for mt.Kind() == reflect.Ptr || mt.Kind() == reflect.Slice || mt.Kind() == reflect.Array {
+		mt = mt.Elem()
+	}
+	sm := NewModel(reflect.New(mt).Interface(), m.ctx)
+	sm.As = m.As
+	if err := sm.setDeletedAt(t); err != nil {
+		return err
+	}
+
+	cols := columns.NewColumnsWithAlias(sm.TableName(), sm.As, sm.IDField())
+	cols.Add(col)
+	_, err := q.Connection.Dialect.UpdateQuery(q.Connection, sm, cols, *q)
+	return q.Connection.translateError(err)
+}
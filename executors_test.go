@@ -2,6 +2,7 @@ package pop
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -465,6 +466,233 @@ func Test_Create_With_Slice(t *testing.T) {
 	})
 }
 
+func Test_CreateMany(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		count, err := tx.Count(&User{})
+		r.NoError(err)
+		users := Users{
+			{Name: nulls.NewString("Mark Bates")},
+			{Name: nulls.NewString("Larry M. Jordan")},
+			{Name: nulls.NewString("Pop")},
+			{Name: nulls.NewString("Soda")},
+			{Name: nulls.NewString("Fizz")},
+		}
+		r.NoError(tx.CreateMany(&users, WithBatchSize(2)))
+
+		ctx, err := tx.Count(&User{})
+		r.NoError(err)
+		r.Equal(count+5, ctx)
+
+		for _, u := range users {
+			r.NotZero(u.ID)
+			r.NotZero(u.CreatedAt)
+			r.NotZero(u.UpdatedAt)
+
+			found := User{}
+			r.NoError(tx.Find(&found, u.ID))
+			r.Equal(u.Name, found.Name)
+		}
+	})
+}
+
+func Test_CreateMany_PresetIDs(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		users := Users{
+			{ID: 900001, Name: nulls.NewString("Mark Bates")},
+			{ID: 900002, Name: nulls.NewString("Larry M. Jordan")},
+		}
+		r.NoError(tx.CreateMany(&users))
+		r.Equal(900001, users[0].ID)
+		r.Equal(900002, users[1].ID)
+
+		found := User{}
+		r.NoError(tx.Find(&found, 900002))
+		r.Equal("Larry M. Jordan", found.Name.String)
+
+		mixed := Users{
+			{ID: 900003, Name: nulls.NewString("Pop")},
+			{Name: nulls.NewString("Soda")},
+		}
+		r.Error(tx.CreateMany(&mixed))
+	})
+}
+
+func Test_CreateMany_UUID(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		id, err := uuid.NewV4()
+		r.NoError(err)
+		songs := []Song{
+			{Title: "Automatic Buffalo"},
+			{ID: id, Title: "Manual Buffalo"},
+		}
+		r.NoError(tx.CreateMany(&songs))
+		r.NotEqual(uuid.Nil, songs[0].ID)
+		r.Equal(id, songs[1].ID)
+
+		found := Song{}
+		r.NoError(tx.Find(&found, songs[0].ID))
+		r.Equal("Automatic Buffalo", found.Title)
+	})
+}
+
+func Test_CreateMany_Callbacks(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		users := CallbacksUsers{{}, {}, {}}
+		r.NoError(tx.CreateMany(&users))
+		for _, u := range users {
+			r.NotZero(u.ID)
+			r.Equal("BeforeSave", u.BeforeS)
+			r.Equal("BeforeCreate", u.BeforeC)
+			r.Equal("AfterCreate", u.AfterC)
+			r.Equal("AfterSave", u.AfterS)
+		}
+
+		r.Error(tx.CreateMany(&CallbacksUser{}))
+	})
+}
+
+func Test_Upsert(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		composer := Composer{Name: "Bach"}
+		r.NoError(tx.Upsert(&composer, []string{"id"}))
+		r.NotZero(composer.ID)
+
+		created := Composer{}
+		r.NoError(tx.Find(&created, composer.ID))
+
+		composer = Composer{ID: composer.ID, Name: "J.S. Bach"}
+		r.NoError(tx.Upsert(&composer, []string{"id"}))
+		r.Equal(created.ID, composer.ID)
+		r.Equal(created.CreatedAt.Unix(), composer.CreatedAt.Unix())
+
+		found := Composer{}
+		r.NoError(tx.Find(&found, composer.ID))
+		r.Equal("J.S. Bach", found.Name)
+		r.Equal(created.CreatedAt.Unix(), found.CreatedAt.Unix())
+
+		count, err := tx.Count(&Composer{})
+		r.NoError(err)
+		r.Equal(1, count)
+
+		r.Error(tx.Upsert(&composer, []string{"id"}, "unknown"))
+		r.Error(tx.Upsert(&composer, nil))
+	})
+}
+
+func Test_Upsert_Version(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		book := LockedBook{Title: "Pop"}
+		r.NoError(tx.Create(&book))
+		r.Equal(0, book.LockVersion)
+
+		upsert := LockedBook{ID: book.ID, Title: "Soda"}
+		r.NoError(tx.Upsert(&upsert, []string{"id"}))
+		r.Equal(1, upsert.LockVersion)
+
+		found := LockedBook{}
+		r.NoError(tx.Find(&found, book.ID))
+		r.Equal("Soda", found.Title)
+		r.Equal(1, found.LockVersion)
+
+		r.ErrorIs(tx.Update(&book), ErrStaleObject)
+	})
+}
+
+func Test_Upsert_SoftDeleted(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		book := SoftBook{Title: "Pop"}
+		r.NoError(tx.Create(&book))
+		r.NoError(tx.Destroy(&book))
+
+		upsert := SoftBook{ID: book.ID, Title: "Soda"}
+		r.NoError(tx.Upsert(&upsert, []string{"id"}, "title"))
+
+		found := SoftBook{}
+		r.NoError(tx.Find(&found, book.ID))
+		r.Equal("Soda", found.Title)
+		r.False(found.DeletedAt.Valid)
+	})
+}
+
+func Test_Upsert_UUID(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		song := Song{Title: "Automatic Buffalo", ComposedByID: 1}
+		r.NoError(tx.Upsert(&song, []string{"id"}))
+		r.NotEqual(uuid.Nil, song.ID)
+
+		update := Song{ID: song.ID, Title: "Manual Buffalo", ComposedByID: 2}
+		r.NoError(tx.Upsert(&update, []string{"id"}, "title"))
+
+		found := Song{}
+		r.NoError(tx.Find(&found, song.ID))
+		r.Equal("Manual Buffalo", found.Title)
+		r.Equal(1, found.ComposedByID)
+	})
+}
+
+func Test_UpsertIgnore(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		composer := Composer{Name: "Bach"}
+		r.NoError(tx.Create(&composer))
+
+		r.NoError(tx.UpsertIgnore(&Composer{ID: composer.ID, Name: "Handel"}, "id"))
+
+		found := Composer{}
+		r.NoError(tx.Find(&found, composer.ID))
+		r.Equal("Bach", found.Name)
+
+		other := Composer{Name: "Handel"}
+		r.NoError(tx.UpsertIgnore(&other))
+		r.NotZero(other.ID)
+		r.NotEqual(composer.ID, other.ID)
+	})
+}
+
 func Test_Create_With_Non_ID_PK(t *testing.T) {
 	if PDB == nil {
 		t.Skip("skipping integration tests")
@@ -1949,6 +2177,116 @@ func Test_TruncateAll(t *testing.T) {
 	})
 }
 
+func Test_Update_OptimisticLocking(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		book := LockedBook{Title: "Pop Book"}
+		r.NoError(tx.Create(&book))
+		r.Equal(0, book.LockVersion)
+
+		first := LockedBook{}
+		r.NoError(tx.Find(&first, book.ID))
+		second := LockedBook{}
+		r.NoError(tx.Find(&second, book.ID))
+
+		var update string
+		tx.Use(func(stmt *Statement, next func(*Statement) error) error {
+			if strings.HasPrefix(stmt.SQL, "UPDATE") {
+				update = stmt.SQL
+			}
+			return next(stmt)
+		})
+
+		first.Title = "First"
+		r.NoError(tx.Update(&first))
+		r.Equal(1, first.LockVersion)
+		r.Contains(update, fmt.Sprintf(".%s = ", tx.Dialect.Quote("lock_version")))
+
+		second.Title = "Second"
+		r.ErrorIs(tx.Update(&second), ErrStaleObject)
+		r.ErrorIs(tx.Save(&second), ErrStaleObject)
+		r.Equal(0, second.LockVersion)
+
+		first.Title = "Columns"
+		r.NoError(tx.UpdateColumns(&first, "title"))
+		r.Equal(2, first.LockVersion)
+
+		found := LockedBook{}
+		r.NoError(tx.Find(&found, book.ID))
+		r.Equal("Columns", found.Title)
+		r.Equal(2, found.LockVersion)
+
+		r.NoError(tx.Destroy(&found))
+		r.ErrorIs(tx.Update(&first), ErrStaleObject)
+	})
+}
+
+func Test_Destroy_SoftDelete(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		book := SoftBook{Title: "Pop Book"}
+		r.NoError(tx.Create(&book))
+
+		r.NoError(tx.Destroy(&book))
+		r.True(book.DeletedAt.Valid)
+		r.Error(tx.Find(&SoftBook{}, book.ID))
+
+		found := SoftBook{}
+		r.NoError(tx.Unscoped().Find(&found, book.ID))
+		r.True(found.DeletedAt.Valid)
+
+		r.NoError(tx.Restore(&book))
+		r.False(book.DeletedAt.Valid)
+		r.NoError(tx.Find(&found, book.ID))
+		r.False(found.DeletedAt.Valid)
+
+		r.NoError(tx.HardDestroy(&book))
+		r.Error(tx.Unscoped().Find(&found, book.ID))
+
+		r.Error(tx.Restore(&User{}))
+	})
+}
+
+func Test_Delete_SoftDelete(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		books := []SoftBook{{Title: "A"}, {Title: "B"}, {Title: "C"}}
+		r.NoError(tx.Create(&books))
+
+		r.NoError(tx.Where("title in (?)", "A", "B").Delete(&SoftBook{}))
+
+		count, err := tx.Count(&SoftBook{})
+		r.NoError(err)
+		r.Equal(1, count)
+
+		count, err = tx.Unscoped().Count(&SoftBook{})
+		r.NoError(err)
+		r.Equal(3, count)
+
+		r.NoError(tx.OnlyDeleted().Where("title = ?", "A").Delete(&SoftBook{}))
+		count, err = tx.Unscoped().Count(&SoftBook{})
+		r.NoError(err)
+		r.Equal(2, count)
+
+		r.NoError(tx.Unscoped().Delete(&SoftBook{}))
+		count, err = tx.Unscoped().Count(&SoftBook{})
+		r.NoError(err)
+		r.Equal(0, count)
+	})
+}
+
 func Test_Delete(t *testing.T) {
 	if PDB == nil {
 		t.Skip("skipping integration tests")
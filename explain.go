@@ -0,0 +1,256 @@
+package pop
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop/v6/logging"
+)
+
+// ExplainSlowQueries is the duration after which, in Debug mode, the plan of
+// a select is logged. Zero disables the plan logging.
+var ExplainSlowQueries time.Duration
+
+const (
+	// ExplainJSON asks for the plan in JSON, which is parsed into the nodes
+	// of the plan. It is the default of the postgres and mysql dialects,
+	// while cockroach and sqlite return an error for it.
+	ExplainJSON = "json"
+	// ExplainText asks for the plan in the text format of the database.
+	ExplainText = "text"
+)
+
+// ExplainOptions change how the plan of a query is explained.
+type ExplainOptions struct {
+	// Analyze runs the query, so the plan includes the actual timings and
+	// row counts.
+	Analyze bool
+	// Format of the plan, either ExplainJSON or ExplainText. Defaults to the
+	// most structured format of the dialect.
+	Format string
+}
+
+// ExplainPlan is the execution plan of a query.
+type ExplainPlan struct {
+	// Raw is the plan as returned by the database.
+	Raw string
+	// Nodes are the root nodes of the plan, when the format of the plan
+	// could be parsed.
+	Nodes []*PlanNode
+}
+
+// PlanNode is a step of an execution plan.
+type PlanNode struct {
+	// Detail describes the step, such as "Seq Scan on users".
+	Detail string
+	// Properties are the other attributes of the step reported by the
+	// database, such as costs or row estimates.
+	Properties map[string]interface{}
+	// Children are the steps whose output is used by this one.
+	Children []*PlanNode
+}
+
+// String returns the raw plan.
+func (p ExplainPlan) String() string {
+	return p.Raw
+}
+
+// Explain returns the execution plan of the query for the given model.
+//
+//	plan, err := c.Where("name = ?", "Mark").Explain(&[]User{}, pop.ExplainOptions{Analyze: true})
+//	fmt.Println(plan.Raw)
+func (q *Query) Explain(model interface{}, opts ExplainOptions) (*ExplainPlan, error) {
+	d, ok := q.Connection.Dialect.(explainable)
+	if !ok {
+		return nil, fmt.Errorf("EXPLAIN is not supported by the %s dialect", q.Connection.Dialect.Name())
+	}
+	if err := q.checkClauses(); err != nil {
+		return nil, err
+	}
+	switch opts.Format {
+	case "", ExplainJSON, ExplainText:
+	default:
+		return nil, fmt.Errorf("unknown EXPLAIN format '%s'", opts.Format)
+	}
+
+	query, args := q.ToSQL(NewModel(model, q.Connection.Context()))
+	var plan *ExplainPlan
+	err := q.Connection.timeFunc("Explain", func() error {
+		var err error
+		plan, err = d.Explain(q.Connection, query, args, opts)
+		return err
+	})
+	return plan, err
+}
+
+// explainSlowQuery logs the plan of a select which took longer than
+// ExplainSlowQueries.
+func explainSlowQuery(c *Connection, elapsed time.Duration, query string, args ...interface{}) {
+	if !Debug || ExplainSlowQueries <= 0 || elapsed < ExplainSlowQueries {
+		return
+	}
+	d, ok := c.Dialect.(explainable)
+	if !ok {
+		return
+	}
+	plan, err := d.Explain(c, query, args, ExplainOptions{Format: ExplainText})
+	if err != nil {
+		c.log(logging.Debug, "couldn't explain slow query (%s): %s: %v", elapsed, query, err)
+		return
+	}
+	c.log(logging.Debug, "slow query (%s): %s\n%s", elapsed, query, plan.Raw)
+}
+
+// explainRows runs an EXPLAIN statement and returns its rows, with the
+// values of each row formatted as strings.
+func explainRows(c *Connection, stmt string, args ...interface{}) ([][]string, error) {
+	c.log(logging.SQL, stmt, args...)
+	rows, err := c.readStore().QueryxContext(c.Context(), stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := [][]string{}
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return nil, err
+		}
+		row := make([]string, len(values))
+		for i, v := range values {
+			switch t := v.(type) {
+			case nil:
+				row[i] = ""
+			case []byte:
+				row[i] = string(t)
+			default:
+				row[i] = fmt.Sprintf("%v", t)
+			}
+		}
+		res = append(res, row)
+	}
+	return res, rows.Err()
+}
+
+// explainText returns the plan made of the text rows of an EXPLAIN statement.
+func explainText(c *Connection, stmt string, args ...interface{}) (*ExplainPlan, error) {
+	rows, err := explainRows(c, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = strings.Join(row, "\t")
+	}
+	return &ExplainPlan{Raw: strings.Join(lines, "\n")}, nil
+}
+
+// explainJSON returns the plan of an EXPLAIN statement returning a single
+// JSON document, with the nodes built by parse.
+func explainJSON(c *Connection, parse func(interface{}) []*PlanNode, stmt string, args ...interface{}) (*ExplainPlan, error) {
+	rows, err := explainRows(c, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return nil, fmt.Errorf("EXPLAIN returned no plan")
+	}
+
+	plan := &ExplainPlan{Raw: rows[0][0]}
+	var doc interface{}
+	if err := json.Unmarshal([]byte(plan.Raw), &doc); err != nil {
+		return nil, fmt.Errorf("couldn't parse EXPLAIN output: %w", err)
+	}
+	plan.Nodes = parse(doc)
+	return plan, nil
+}
+
+// postgresPlanNodes returns the nodes of a plan in the JSON format of
+// postgres: a list of objects with a "Plan" tree, whose children are under
+// "Plans".
+func postgresPlanNodes(doc interface{}) []*PlanNode {
+	var walk func(p map[string]interface{}) *PlanNode
+	walk = func(p map[string]interface{}) *PlanNode {
+		n := &PlanNode{Properties: map[string]interface{}{}}
+		for k, v := range p {
+			if k == "Plans" {
+				continue
+			}
+			n.Properties[k] = v
+		}
+		n.Detail = fmt.Sprint(p["Node Type"])
+		if r, ok := p["Relation Name"]; ok {
+			n.Detail = fmt.Sprintf("%s on %v", n.Detail, r)
+		}
+		children, _ := p["Plans"].([]interface{})
+		for _, c := range children {
+			if cp, ok := c.(map[string]interface{}); ok {
+				n.Children = append(n.Children, walk(cp))
+			}
+		}
+		return n
+	}
+
+	nodes := []*PlanNode{}
+	list, _ := doc.([]interface{})
+	for _, e := range list {
+		m, _ := e.(map[string]interface{})
+		if p, ok := m["Plan"].(map[string]interface{}); ok {
+			nodes = append(nodes, walk(p))
+		}
+	}
+	return nodes
+}
+
+// mysqlPlanNodes returns the nodes of a plan in the JSON format of mysql: an
+// object with a single "query_block", kept as the properties of one node.
+func mysqlPlanNodes(doc interface{}) []*PlanNode {
+	m, _ := doc.(map[string]interface{})
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	nodes := []*PlanNode{}
+	for _, k := range keys {
+		props, _ := m[k].(map[string]interface{})
+		nodes = append(nodes, &PlanNode{Detail: k, Properties: props})
+	}
+	return nodes
+}
+
+// explainSQLite returns the plan of an EXPLAIN QUERY PLAN statement, whose
+// rows are the id, parent id, an unused column and the detail of each node.
+func explainSQLite(c *Connection, stmt string, args ...interface{}) (*ExplainPlan, error) {
+	rows, err := explainRows(c, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ExplainPlan{}
+	byID := map[string]*PlanNode{}
+	depth := map[string]int{}
+	lines := []string{}
+	for _, row := range rows {
+		if len(row) < 4 {
+			return nil, fmt.Errorf("unexpected EXPLAIN QUERY PLAN row %v", row)
+		}
+		id, parent, detail := row[0], row[1], row[3]
+		n := &PlanNode{Detail: detail}
+		byID[id] = n
+		if p, ok := byID[parent]; ok {
+			p.Children = append(p.Children, n)
+			depth[id] = depth[parent] + 1
+		} else {
+			plan.Nodes = append(plan.Nodes, n)
+		}
+		lines = append(lines, strings.Repeat("  ", depth[id])+detail)
+	}
+	plan.Raw = strings.Join(lines, "\n")
+	return plan, nil
+}
@@ -0,0 +1,127 @@
+package pop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PostgresPlanNodes(t *testing.T) {
+	r := require.New(t)
+
+	var doc interface{}
+	r.NoError(json.Unmarshal([]byte(`[{"Plan": {"Node Type": "Hash Join", "Total Cost": 12.5, "Plans": [
+		{"Node Type": "Seq Scan", "Relation Name": "books", "Alias": "books"},
+		{"Node Type": "Hash", "Plans": [{"Node Type": "Index Scan", "Relation Name": "users"}]}
+	]}, "Planning Time": 0.1}]`), &doc))
+
+	nodes := postgresPlanNodes(doc)
+	r.Len(nodes, 1)
+	r.Equal("Hash Join", nodes[0].Detail)
+	r.Equal(12.5, nodes[0].Properties["Total Cost"])
+	r.NotContains(nodes[0].Properties, "Plans")
+	r.Len(nodes[0].Children, 2)
+	r.Equal("Seq Scan on books", nodes[0].Children[0].Detail)
+	r.Equal("Index Scan on users", nodes[0].Children[1].Children[0].Detail)
+}
+
+func Test_MySQLPlanNodes(t *testing.T) {
+	r := require.New(t)
+
+	var doc interface{}
+	r.NoError(json.Unmarshal([]byte(`{"query_block": {"select_id": 1, "table": {"table_name": "users"}}}`), &doc))
+
+	nodes := mysqlPlanNodes(doc)
+	r.Len(nodes, 1)
+	r.Equal("query_block", nodes[0].Detail)
+	r.Equal(1.0, nodes[0].Properties["select_id"])
+}
+
+func Test_Explain_Unsupported(t *testing.T) {
+	r := require.New(t)
+
+	_, err := (&cockroach{}).Explain(nil, "SELECT 1", nil, ExplainOptions{Format: ExplainJSON})
+	r.EqualError(err, "EXPLAIN in JSON is not supported by CockroachDB")
+
+	m := &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{Dialect: nameMySQL}}, version: "8.0.16"}
+	_, err = m.Explain(nil, "SELECT 1", nil, ExplainOptions{Analyze: true})
+	r.EqualError(err, "EXPLAIN ANALYZE is not supported by MySQL before 8.0.18")
+}
+
+func Test_Query_Explain(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		r.NoError(tx.Create(&User{Name: nulls.NewString("Mark")}))
+
+		if tx.Dialect.Name() == nameSQLite3 {
+			plan, err := tx.Where("name = ?", "Mark").Explain(&[]User{}, ExplainOptions{})
+			r.NoError(err)
+			r.NotEmpty(plan.Nodes)
+			r.Contains(plan.Raw, "users")
+
+			_, err = tx.Q().Explain(&[]User{}, ExplainOptions{Analyze: true})
+			r.Error(err)
+			return
+		}
+
+		plan, err := tx.Where("name = ?", "Mark").Explain(&[]User{}, ExplainOptions{Format: ExplainText})
+		r.NoError(err)
+		r.NotEmpty(plan.Raw)
+
+		if tx.Dialect.Name() == namePostgreSQL {
+			plan, err = tx.Where("name = ?", "Mark").Explain(&[]User{}, ExplainOptions{Analyze: true})
+			r.NoError(err)
+			r.Len(plan.Nodes, 1)
+			r.Contains(plan.Nodes[0].Properties, "Actual Rows")
+		}
+
+		_, err = tx.Q().Explain(&[]User{}, ExplainOptions{Format: "yaml"})
+		r.Error(err)
+	})
+}
+
+func Test_ExplainSlowQueries(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		if _, ok := tx.Dialect.(explainable); !ok {
+			t.Skip("EXPLAIN is not supported by the dialect")
+		}
+
+		logs := []string{}
+		oldTxLog := txlog
+		defer func() { txlog = oldTxLog }()
+		SetTxLogger(func(lvl logging.Level, anon interface{}, s string, args ...interface{}) {
+			if lvl == logging.Debug {
+				logs = append(logs, fmt.Sprintf(s, args...))
+			}
+		})
+
+		Debug = true
+		defer func() { Debug = false }()
+		ExplainSlowQueries = time.Nanosecond
+		defer func() { ExplainSlowQueries = 0 }()
+
+		r.NoError(tx.Where("name = ?", "Mark").All(&[]User{}))
+		r.Len(logs, 1)
+		r.True(strings.HasPrefix(logs[0], "slow query ("), logs[0])
+		r.Contains(logs[0], "users")
+
+		ExplainSlowQueries = time.Hour
+		r.NoError(tx.All(&[]User{}))
+		r.Len(logs, 1)
+	})
+}
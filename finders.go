@@ -66,12 +66,15 @@ func (c *Connection) First(model interface{}) error {
 //
 //	q.Where("name = ?", "mark").First(&User{})
 func (q *Query) First(model interface{}) error {
+	if err := q.checkClauses(); err != nil {
+		return err
+	}
 	var m *Model
 	err := q.Connection.timeFunc("First", func() error {
 		q.Limit(1)
 		m = NewModel(model, q.Connection.Context())
 		if err := q.Connection.Dialect.SelectOne(q.Connection, m, *q); err != nil {
-			return err
+			return q.Connection.notFoundError(err)
 		}
 		return m.afterFind(q.Connection, false)
 	})
@@ -103,13 +106,16 @@ func (c *Connection) Last(model interface{}) error {
 //
 //	q.Where("name = ?", "mark").Last(&User{})
 func (q *Query) Last(model interface{}) error {
+	if err := q.checkClauses(); err != nil {
+		return err
+	}
 	var m *Model
 	err := q.Connection.timeFunc("Last", func() error {
 		q.Limit(1)
 		q.Order("created_at DESC, id DESC")
 		m = NewModel(model, q.Connection.Context())
 		if err := q.Connection.Dialect.SelectOne(q.Connection, m, *q); err != nil {
-			return err
+			return q.Connection.notFoundError(err)
 		}
 		return m.afterFind(q.Connection, false)
 	})
@@ -141,12 +147,22 @@ func (c *Connection) All(models interface{}) error {
 //
 //	q.Where("name = ?", "mark").All(&[]User{})
 func (q *Query) All(models interface{}) error {
+	if err := q.checkClauses(); err != nil {
+		return err
+	}
 	var m *Model
 	err := q.Connection.timeFunc("All", func() error {
 		m = NewModel(models, q.Connection.Context())
+		if q.CursorPaginator != nil {
+			if err := q.selectByCursor(m); err != nil {
+				return err
+			}
+			return m.afterFind(q.Connection, false)
+		}
+
 		err := q.Connection.Dialect.SelectMany(q.Connection, m, *q)
 		if err != nil {
-			return err
+			return q.Connection.translateError(err)
 		}
 
 		err = q.paginateModel(models)
@@ -287,7 +303,7 @@ func (q *Query) eagerDefaultAssociations(model interface{}) error {
 			return err
 		}
 
-		if err == sql.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			continue
 		}
 
@@ -327,6 +343,7 @@ func (q *Query) Exists(model interface{}) (bool, error) {
 		tmpQuery.Paginator = nil
 		tmpQuery.orderClauses = clauses{}
 		tmpQuery.limitResults = 0
+		tmpQuery.lock = RowLock{}
 		query, args := tmpQuery.ToSQL(NewModel(model, tmpQuery.Connection.Context()))
 
 		// when query contains custom selected fields / executed using RawQuery,
@@ -340,8 +357,8 @@ func (q *Query) Exists(model interface{}) (bool, error) {
 		}
 
 		existsQuery := fmt.Sprintf("SELECT EXISTS (%s)", query)
-		txlog(logging.SQL, q.Connection, existsQuery, args...)
-		return q.Connection.Store.Get(&res, existsQuery, args...)
+		q.Connection.log(logging.SQL, existsQuery, args...)
+		return q.Connection.readStore().Get(&res, existsQuery, args...)
 	})
 	return res, err
 }
@@ -364,15 +381,117 @@ func (q Query) Count(model interface{}) (int, error) {
 //
 //	q.Where("sex = ?", "f").Count(&User{}, "name")
 func (q Query) CountByField(model interface{}, field string) (int, error) {
+	res := &rowCount{}
+	err := q.selectScalar("CountByField", model, fmt.Sprintf("COUNT(%s) AS row_count", field), res)
+	return res.Count, err
+}
+
+// Sum selects the sum of the values of a column of the records in the
+// database into dest. The sum of no records is NULL, so dest should be a
+// nullable type, such as nulls.Float64 or sql.NullInt64, to tell it apart.
+//
+//	total := nulls.Float64{}
+//	c.Sum(&Order{}, "total", &total)
+func (c *Connection) Sum(model interface{}, column string, dest interface{}) error {
+	return Q(c).Sum(model, column, dest)
+}
+
+// Sum selects the sum of the values of a column of the records in the
+// database into dest. The sum of no records is NULL, so dest should be a
+// nullable type, such as nulls.Float64 or sql.NullInt64, to tell it apart.
+//
+//	count := sql.NullInt64{}
+//	q.Where("user_id = ?", id).Sum(&Order{}, "items", &count)
+func (q Query) Sum(model interface{}, column string, dest interface{}) error {
+	return q.aggregate("Sum", model, "SUM", column, dest)
+}
+
+// Avg selects the average of the values of a column of the records in the
+// database into dest. The average of no records is NULL, so dest should be
+// a nullable type, such as nulls.Float64, to tell it apart.
+//
+//	avg := nulls.Float64{}
+//	c.Avg(&Order{}, "total", &avg)
+func (c *Connection) Avg(model interface{}, column string, dest interface{}) error {
+	return Q(c).Avg(model, column, dest)
+}
+
+// Avg selects the average of the values of a column of the records in the
+// database into dest. The average of no records is NULL, so dest should be
+// a nullable type, such as nulls.Float64, to tell it apart.
+//
+//	avg := nulls.Float64{}
+//	q.Where("user_id = ?", id).Avg(&Order{}, "total", &avg)
+func (q Query) Avg(model interface{}, column string, dest interface{}) error {
+	return q.aggregate("Avg", model, "AVG", column, dest)
+}
+
+// Min selects the smallest value of a column of the records in the database
+// into dest, whose type matches the column, such as nulls.Time for a
+// timestamp. The smallest value of no records is NULL, so dest should be a
+// nullable type to tell it apart.
+//
+//	first := nulls.Time{}
+//	c.Min(&Order{}, "created_at", &first)
+func (c *Connection) Min(model interface{}, column string, dest interface{}) error {
+	return Q(c).Min(model, column, dest)
+}
+
+// Min selects the smallest value of a column of the records in the database
+// into dest, whose type matches the column, such as nulls.Time for a
+// timestamp. The smallest value of no records is NULL, so dest should be a
+// nullable type to tell it apart.
+//
+//	first := nulls.Time{}
+//	q.Where("user_id = ?", id).Min(&Order{}, "created_at", &first)
+func (q Query) Min(model interface{}, column string, dest interface{}) error {
+	return q.aggregate("Min", model, "MIN", column, dest)
+}
+
+// Max selects the largest value of a column of the records in the database
+// into dest, whose type matches the column, such as nulls.String for a text
+// column. The largest value of no records is NULL, so dest should be a
+// nullable type to tell it apart.
+//
+//	last := nulls.Time{}
+//	c.Max(&Order{}, "created_at", &last)
+func (c *Connection) Max(model interface{}, column string, dest interface{}) error {
+	return Q(c).Max(model, column, dest)
+}
+
+// Max selects the largest value of a column of the records in the database
+// into dest, whose type matches the column, such as nulls.String for a text
+// column. The largest value of no records is NULL, so dest should be a
+// nullable type to tell it apart.
+//
+//	last := nulls.Time{}
+//	q.Where("user_id = ?", id).Max(&Order{}, "created_at", &last)
+func (q Query) Max(model interface{}, column string, dest interface{}) error {
+	return q.aggregate("Max", model, "MAX", column, dest)
+}
+
+func (q Query) aggregate(name string, model interface{}, fn string, column string, dest interface{}) error {
+	if d, ok := q.Connection.Dialect.(scalarWrapper); ok {
+		dest = d.WrapScalar(dest)
+	}
+	return q.selectScalar(name, model, fmt.Sprintf("%s(%s)", fn, column), dest)
+}
+
+// selectScalar selects the given expression over the records matched by the
+// query, ignoring its order and pagination, and scans the result into dest.
+// The expression refers to the columns selected by the query.
+func (q Query) selectScalar(name string, model interface{}, expr string, dest interface{}) error {
 	tmpQuery := Q(q.Connection)
 	q.Clone(tmpQuery) // avoid meddling with original query
 
-	res := &rowCount{}
-
-	err := tmpQuery.Connection.timeFunc("CountByField", func() error {
+	return tmpQuery.Connection.timeFunc(name, func() error {
+		if err := tmpQuery.checkDistinct(); err != nil {
+			return err
+		}
 		tmpQuery.Paginator = nil
 		tmpQuery.orderClauses = clauses{}
 		tmpQuery.limitResults = 0
+		tmpQuery.lock = RowLock{}
 		query, args := tmpQuery.ToSQL(NewModel(model, q.Connection.Context()))
 		// when query contains custom selected fields / executed using RawQuery,
 		//	sql may already contains limit and offset
@@ -385,11 +504,40 @@ func (q Query) CountByField(model interface{}, field string) (int, error) {
 			query = query[0 : len(query)-len(foundLimit)]
 		}
 
-		countQuery := fmt.Sprintf("SELECT COUNT(%s) AS row_count FROM (%s) a", field, query)
-		txlog(logging.SQL, q.Connection, countQuery, args...)
-		return q.Connection.Store.Get(res, countQuery, args...)
+		scalarQuery := fmt.Sprintf("SELECT %s FROM (%s) a", expr, query)
+		q.Connection.log(logging.SQL, scalarQuery, args...)
+		return q.Connection.readStore().Get(dest, scalarQuery, args...)
+	})
+}
+
+// Pluck selects a single column of the records in the database into dest,
+// which must be a pointer to a slice of scalars.
+//
+//	names := []string{}
+//	c.Pluck(&User{}, "name", &names)
+func (c *Connection) Pluck(model interface{}, column string, dest interface{}) error {
+	return Q(c).Pluck(model, column, dest)
+}
+
+// Pluck selects a single column of the records in the database into dest,
+// which must be a pointer to a slice of scalars. The order and pagination of
+// the query are respected.
+//
+//	ids := []int{}
+//	q.Where("alive = ?", true).Order("name").Pluck(&User{}, "id", &ids)
+func (q Query) Pluck(model interface{}, column string, dest interface{}) error {
+	tmpQuery := Q(q.Connection)
+	q.Clone(tmpQuery) // avoid meddling with original query
+
+	return tmpQuery.Connection.timeFunc("Pluck", func() error {
+		if err := tmpQuery.checkClauses(); err != nil {
+			return err
+		}
+		tmpQuery.addColumns = []string{column}
+		query, args := tmpQuery.ToSQL(NewModel(model, q.Connection.Context()))
+		q.Connection.log(logging.SQL, query, args...)
+		return q.Connection.readStore().Select(dest, query, args...)
 	})
-	return res.Count, err
 }
 
 type rowCount struct {
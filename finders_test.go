@@ -1,6 +1,8 @@
 package pop
 
 import (
+	"database/sql"
+	"fmt"
 	"testing"
 
 	"github.com/gobuffalo/nulls"
@@ -906,6 +908,96 @@ func Test_Count_RawQuery(t *testing.T) {
 	})
 }
 
+func Test_Aggregates(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		sum := nulls.Float64{}
+		r.NoError(tx.Sum(&User{}, "price", &sum))
+		r.False(sum.Valid)
+
+		first := nulls.Time{}
+		r.NoError(tx.Min(&User{}, "created_at", &first))
+		r.False(first.Valid)
+
+		for i, name := range []string{"Mark", "Mark", "Paul"} {
+			r.NoError(tx.Create(&User{Name: nulls.NewString(name), Price: nulls.NewFloat64(float64(i+1) * 1.5)}))
+		}
+
+		r.NoError(tx.Sum(&User{}, "price", &sum))
+		r.Equal(nulls.NewFloat64(9.0), sum)
+
+		avg := nulls.Float64{}
+		r.NoError(tx.Where("name = ?", "Mark").Avg(&User{}, "price", &avg))
+		r.Equal(nulls.NewFloat64(2.25), avg)
+
+		min := nulls.Float64{}
+		r.NoError(tx.Order("id desc").Limit(1).Min(&User{}, "price", &min))
+		r.Equal(nulls.NewFloat64(1.5), min)
+
+		max := nulls.Float64{}
+		r.NoError(tx.Max(&User{}, "price", &max))
+		r.Equal(nulls.NewFloat64(4.5), max)
+
+		r.NoError(tx.Select("name", "MAX(price) AS price").GroupBy("name").Sum(&User{}, "price", &sum))
+		r.Equal(nulls.NewFloat64(7.5), sum)
+
+		ids := sql.NullInt64{}
+		r.NoError(tx.Sum(&User{}, "id", &ids))
+		r.True(ids.Valid)
+
+		r.NoError(tx.Min(&User{}, "created_at", &first))
+		r.True(first.Valid)
+
+		name := nulls.String{}
+		r.NoError(tx.Max(&User{}, "name", &name))
+		r.Equal(nulls.NewString("Paul"), name)
+
+		c, err := tx.Distinct().Select("name").Count(&User{})
+		r.NoError(err)
+		r.Equal(2, c)
+	})
+}
+
+func Test_Pluck(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		users := []User{}
+		for _, name := range []string{"Paul", "Mark", "Mark"} {
+			u := User{Name: nulls.NewString(name)}
+			r.NoError(tx.Create(&u))
+			users = append(users, u)
+		}
+
+		names := []string{}
+		r.NoError(tx.Order("id").Pluck(&User{}, "name", &names))
+		r.Equal([]string{"Paul", "Mark", "Mark"}, names)
+
+		names = []string{}
+		r.NoError(tx.Distinct().Order("name").Pluck(&User{}, "name", &names))
+		r.Equal([]string{"Mark", "Paul"}, names)
+
+		ids := []int{}
+		r.NoError(tx.Where("name = ?", "Mark").Order("id desc").Limit(1).Pluck(&User{}, "id", &ids))
+		r.Equal([]int{users[2].ID}, ids)
+
+		if !supportsDistinctOn(tx.Dialect) {
+			r.EqualError(tx.Distinct("name").Pluck(&User{}, "id", &ids), fmt.Sprintf("DISTINCT ON is not supported by the %s dialect", tx.Dialect.Name()))
+			return
+		}
+		ids = []int{}
+		r.NoError(tx.Distinct("name").Order("name, id desc").Pluck(&User{}, "id", &ids))
+		r.Equal([]int{users[2].ID, users[0].ID}, ids)
+	})
+}
+
 func Test_Exists(t *testing.T) {
 	if PDB == nil {
 		t.Skip("skipping integration tests")
@@ -984,3 +1076,47 @@ func Test_FindMultipleInnerHasMany(t *testing.T) {
 		r.Equal(u.Books[0].Writers[0].Friends[0].FirstName, "Frank")
 	})
 }
+
+func Test_Finders_SoftDelete(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		author := SoftAuthor{Name: "Mark"}
+		r.NoError(tx.Create(&author))
+
+		books := []SoftBook{
+			{Title: "Alive", SoftAuthorID: nulls.NewInt(author.ID)},
+			{Title: "Deleted", SoftAuthorID: nulls.NewInt(author.ID)},
+		}
+		r.NoError(tx.Create(&books))
+		r.NoError(tx.Destroy(&books[1]))
+
+		all := []SoftBook{}
+		r.NoError(tx.All(&all))
+		r.Len(all, 1)
+		r.Equal("Alive", all[0].Title)
+
+		r.NoError(tx.Unscoped().All(&all))
+		r.Len(all, 2)
+
+		r.NoError(tx.OnlyDeleted().All(&all))
+		r.Len(all, 1)
+		r.Equal("Deleted", all[0].Title)
+
+		exists, err := tx.Where("title = ?", "Deleted").Exists(&SoftBook{})
+		r.NoError(err)
+		r.False(exists)
+
+		found := SoftAuthor{}
+		r.NoError(tx.EagerPreload("Books").Find(&found, author.ID))
+		r.Len(found.Books, 1)
+		r.Equal("Alive", found.Books[0].Title)
+
+		found = SoftAuthor{}
+		r.NoError(tx.Eager("Books").Find(&found, author.ID))
+		r.Len(found.Books, 1)
+	})
+}
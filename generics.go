@@ -0,0 +1,279 @@
+package pop
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// The generic API below checks the types of models at compile time, on top
+// of the finders and executors taking an interface{}. T is the type of the
+// model struct, not a pointer to it; the functions return an error for
+// pointer types.
+
+// checkModelType reports a T which is not the type of a model struct, such
+// as a pointer to it.
+func checkModelType[T any]() error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return fmt.Errorf("%s is not a model type, use the type of the struct instead", t)
+	}
+	return nil
+}
+
+// Find the record of the model with the given id.
+//
+//	user, err := pop.Find[User](c, 1)
+func Find[T any](c *Connection, id interface{}) (T, error) {
+	var model T
+	if err := checkModelType[T](); err != nil {
+		return model, err
+	}
+	err := c.Find(&model, id)
+	return model, err
+}
+
+// First returns the first record of the model matched by the query, in the
+// order of the query. Without an order, the database picks any record.
+//
+//	user, err := pop.First[User](c.Where("name = ?", "Mark"))
+func First[T any](q *Query) (T, error) {
+	var model T
+	if err := checkModelType[T](); err != nil {
+		return model, err
+	}
+	err := q.First(&model)
+	return model, err
+}
+
+// Last returns the last record of the model matched by the query, ordered by
+// `created_at DESC, id DESC` after the order of the query.
+//
+//	user, err := pop.Last[User](c.Where("name = ?", "Mark"))
+func Last[T any](q *Query) (T, error) {
+	var model T
+	if err := checkModelType[T](); err != nil {
+		return model, err
+	}
+	err := q.Last(&model)
+	return model, err
+}
+
+// All returns the records of the model matched by the query.
+//
+//	users, err := pop.All[User](c.Where("name = ?", "Mark"))
+func All[T any](q *Query) ([]T, error) {
+	models := []T{}
+	if err := checkModelType[T](); err != nil {
+		return models, err
+	}
+	err := q.All(&models)
+	return models, err
+}
+
+// Create the record of the model, see Connection.Create.
+//
+//	user := User{Name: "Mark"}
+//	err := pop.Create(c, &user)
+func Create[T any](c *Connection, model *T, excludeColumns ...string) error {
+	if err := checkModelType[T](); err != nil {
+		return err
+	}
+	return c.Create(model, excludeColumns...)
+}
+
+// TypedQuery is a query for the records of the model T. Its methods build
+// the query like those of Query, and its finders return values of type T.
+type TypedQuery[T any] struct {
+	q *Query
+}
+
+// QueryOf creates a new typed query for the model T on the connection.
+//
+//	users, err := pop.QueryOf[User](c).Where("name = ?", "Mark").Order("id").All()
+func QueryOf[T any](c *Connection) *TypedQuery[T] {
+	return &TypedQuery[T]{q: Q(c)}
+}
+
+// Query returns the underlying query, to use the methods which are not
+// available on TypedQuery.
+func (q *TypedQuery[T]) Query() *Query {
+	return q.q
+}
+
+// Paginator returns the paginator of the query, set by Paginate.
+func (q *TypedQuery[T]) Paginator() *Paginator {
+	return q.q.Paginator
+}
+
+// Where will append a where clause to the query, see Query.Where.
+func (q *TypedQuery[T]) Where(stmt string, args ...interface{}) *TypedQuery[T] {
+	q.q.Where(stmt, args...)
+	return q
+}
+
+// Order will append an order clause to the query, see Query.Order.
+func (q *TypedQuery[T]) Order(stmt string, args ...interface{}) *TypedQuery[T] {
+	q.q.Order(stmt, args...)
+	return q
+}
+
+// Limit will add a limit clause to the query, see Query.Limit.
+func (q *TypedQuery[T]) Limit(limit int) *TypedQuery[T] {
+	q.q.Limit(limit)
+	return q
+}
+
+// Paginate records returned from the database, see Query.Paginate.
+func (q *TypedQuery[T]) Paginate(page int, perPage int) *TypedQuery[T] {
+	q.q.Paginate(page, perPage)
+	return q
+}
+
+// Select allows to query only fields passed as parameter, see Query.Select.
+func (q *TypedQuery[T]) Select(fields ...string) *TypedQuery[T] {
+	q.q.Select(fields...)
+	return q
+}
+
+// Join will append a JOIN clause to the query, see Query.Join.
+func (q *TypedQuery[T]) Join(table string, on string, args ...interface{}) *TypedQuery[T] {
+	q.q.Join(table, on, args...)
+	return q
+}
+
+// LeftJoin will append a LEFT JOIN clause to the query, see Query.LeftJoin.
+func (q *TypedQuery[T]) LeftJoin(table string, on string, args ...interface{}) *TypedQuery[T] {
+	q.q.LeftJoin(table, on, args...)
+	return q
+}
+
+// GroupBy will append a GROUP BY clause to the query, see Query.GroupBy.
+func (q *TypedQuery[T]) GroupBy(field string, fields ...string) *TypedQuery[T] {
+	q.q.GroupBy(field, fields...)
+	return q
+}
+
+// Having will append a HAVING clause to the query, see Query.Having.
+func (q *TypedQuery[T]) Having(condition string, args ...interface{}) *TypedQuery[T] {
+	q.q.Having(condition, args...)
+	return q
+}
+
+// Scope applies the scope function to the query, see Query.Scope.
+func (q *TypedQuery[T]) Scope(sf ScopeFunc) *TypedQuery[T] {
+	q.q.Scope(sf)
+	return q
+}
+
+// Eager will enable associations loading of the model, see Query.Eager.
+func (q *TypedQuery[T]) Eager(fields ...string) *TypedQuery[T] {
+	q.q.Eager(fields...)
+	return q
+}
+
+// EagerPreload will enable associations loading of the model with
+// preloading, see Query.EagerPreload.
+func (q *TypedQuery[T]) EagerPreload(fields ...string) *TypedQuery[T] {
+	q.q.EagerPreload(fields...)
+	return q
+}
+
+// Unscoped will include soft deleted records, see Query.Unscoped.
+func (q *TypedQuery[T]) Unscoped() *TypedQuery[T] {
+	q.q.Unscoped()
+	return q
+}
+
+// ForUpdate will lock the selected rows for update, see Query.ForUpdate.
+func (q *TypedQuery[T]) ForUpdate() *TypedQuery[T] {
+	q.q.ForUpdate()
+	return q
+}
+
+// ForShare will lock the selected rows in shared mode, see Query.ForShare.
+func (q *TypedQuery[T]) ForShare() *TypedQuery[T] {
+	q.q.ForShare()
+	return q
+}
+
+// NoWait will fail instead of waiting for the locked rows, see Query.NoWait.
+func (q *TypedQuery[T]) NoWait() *TypedQuery[T] {
+	q.q.NoWait()
+	return q
+}
+
+// SkipLocked will skip the locked rows, see Query.SkipLocked.
+func (q *TypedQuery[T]) SkipLocked() *TypedQuery[T] {
+	q.q.SkipLocked()
+	return q
+}
+
+// OnlyDeleted will only include soft deleted records, see Query.OnlyDeleted.
+func (q *TypedQuery[T]) OnlyDeleted() *TypedQuery[T] {
+	q.q.OnlyDeleted()
+	return q
+}
+
+// Timeout will bound the duration of the statements of the query, see
+// Query.Timeout.
+func (q *TypedQuery[T]) Timeout(d time.Duration) *TypedQuery[T] {
+	q.q.Timeout(d)
+	return q
+}
+
+// Find the record of the model with the given id, see Query.Find.
+func (q *TypedQuery[T]) Find(id interface{}) (T, error) {
+	var model T
+	if err := checkModelType[T](); err != nil {
+		return model, err
+	}
+	err := q.q.Find(&model, id)
+	return model, err
+}
+
+// First returns the first record matched by the query, see Query.First.
+func (q *TypedQuery[T]) First() (T, error) {
+	return First[T](q.q)
+}
+
+// Last returns the last record matched by the query, see Query.Last.
+func (q *TypedQuery[T]) Last() (T, error) {
+	return Last[T](q.q)
+}
+
+// All returns the records matched by the query, see Query.All.
+func (q *TypedQuery[T]) All() ([]T, error) {
+	return All[T](q.q)
+}
+
+// Count the records matched by the query, see Query.Count.
+func (q *TypedQuery[T]) Count() (int, error) {
+	var model T
+	if err := checkModelType[T](); err != nil {
+		return 0, err
+	}
+	return q.q.Count(&model)
+}
+
+// Exists returns true if the query matches at least one record, see
+// Query.Exists.
+func (q *TypedQuery[T]) Exists() (bool, error) {
+	var model T
+	if err := checkModelType[T](); err != nil {
+		return false, err
+	}
+	return q.q.Exists(&model)
+}
+
+// Each runs fn for every record matched by the query, streaming them from
+// the database, see Query.Each.
+func (q *TypedQuery[T]) Each(fn func(*T) error, opts ...BatchOption) error {
+	var model T
+	if err := checkModelType[T](); err != nil {
+		return err
+	}
+	return q.q.Each(&model, func(m interface{}) error {
+		return fn(m.(*T))
+	}, opts...)
+}
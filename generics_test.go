@@ -0,0 +1,127 @@
+package pop
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Generics_Finders(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		mark := User{Name: nulls.NewString("Mark")}
+		r.NoError(Create(tx, &mark))
+		r.NotZero(mark.ID)
+		paul := User{Name: nulls.NewString("Paul")}
+		r.NoError(Create(tx, &paul))
+
+		u, err := Find[User](tx, mark.ID)
+		r.NoError(err)
+		r.Equal("Mark", u.Name.String)
+
+		_, err = Find[User](tx, -1)
+		r.Error(err)
+
+		u, err = First[User](tx.Where("name IN (?)", "Mark", "Paul"))
+		r.NoError(err)
+		r.Equal(mark.ID, u.ID)
+
+		u, err = Last[User](tx.Where("name IN (?)", "Mark", "Paul"))
+		r.NoError(err)
+		r.Equal(paul.ID, u.ID)
+
+		users, err := All[User](tx.Order("name desc"))
+		r.NoError(err)
+		r.Len(users, 2)
+		r.Equal("Paul", users[0].Name.String)
+	})
+}
+
+func Test_TypedQuery(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		for _, name := range []string{"Mark", "Paul", "John"} {
+			r.NoError(tx.Create(&User{Name: nulls.NewString(name)}))
+		}
+
+		users, err := QueryOf[User](tx).Where("name != ?", "Paul").Order("name").All()
+		r.NoError(err)
+		r.Len(users, 2)
+		r.Equal("John", users[0].Name.String)
+
+		q := QueryOf[User](tx).Order("name").Paginate(2, 2)
+		users, err = q.All()
+		r.NoError(err)
+		r.Len(users, 1)
+		r.Equal("Paul", users[0].Name.String)
+		r.Equal(3, q.Paginator().TotalEntriesSize)
+
+		u, err := QueryOf[User](tx).Where("name = ?", "Paul").First()
+		r.NoError(err)
+		r.Equal("Paul", u.Name.String)
+
+		found, err := QueryOf[User](tx).Find(u.ID)
+		r.NoError(err)
+		r.Equal(u.ID, found.ID)
+
+		count, err := QueryOf[User](tx).Where("name LIKE ?", "%a%").Count()
+		r.NoError(err)
+		r.Equal(2, count)
+
+		exists, err := QueryOf[User](tx).Where("name = ?", "Ringo").Exists()
+		r.NoError(err)
+		r.False(exists)
+
+		names := []string{}
+		r.NoError(QueryOf[User](tx).Order("id").Each(func(u *User) error {
+			names = append(names, u.Name.String)
+			return nil
+		}))
+		r.Equal([]string{"Mark", "Paul", "John"}, names)
+	})
+}
+
+func Test_Generics_PointerType(t *testing.T) {
+	r := require.New(t)
+
+	pg, err := NewConnection(&ConnectionDetails{Dialect: "postgres", Database: "pop_test"})
+	r.NoError(err)
+
+	_, err = Find[*User](pg, 1)
+	r.EqualError(err, "*pop.User is not a model type, use the type of the struct instead")
+	_, err = All[*User](pg.Q())
+	r.Error(err)
+	_, err = QueryOf[*User](pg).First()
+	r.Error(err)
+	_, err = QueryOf[*User](pg).Count()
+	r.Error(err)
+	r.Error(QueryOf[*User](pg).Each(func(**User) error { return nil }))
+}
+
+func Test_TypedQuery_Options(t *testing.T) {
+	r := require.New(t)
+
+	pg, err := NewConnection(&ConnectionDetails{Dialect: "postgres", Database: "pop_test"})
+	r.NoError(err)
+	user := NewModel(&User{}, pg.Context())
+
+	q, _ := QueryOf[User](pg).ForUpdate().SkipLocked().Query().ToSQL(user)
+	r.True(strings.HasSuffix(q, "FOR UPDATE SKIP LOCKED"), q)
+	q, _ = QueryOf[User](pg).ForShare().NoWait().Query().ToSQL(user)
+	r.True(strings.HasSuffix(q, "FOR SHARE NOWAIT"), q)
+
+	tq := QueryOf[User](pg).OnlyDeleted().Timeout(time.Second)
+	r.True(tq.Query().onlyDeleted)
+	r.Equal(time.Second, tq.Query().Connection.timeout)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gobuffalo/genny/v2"
@@ -39,6 +40,31 @@ func Test_New(t *testing.T) {
 	}
 }
 
+func Test_New_YDB(t *testing.T) {
+	r := require.New(t)
+
+	run := genny.DryRunner(context.Background())
+	g, err := New(&Options{
+		Prefix:  "foo",
+		Dialect: "ydb",
+	})
+	r.NoError(err)
+	run.With(g)
+	r.NoError(run.Run())
+
+	res := run.Results()
+	r.Len(res.Files, 1)
+
+	deets, err := pop.ParseConfig(strings.NewReader(res.Files[0].String()))
+	r.NoError(err)
+	r.Len(deets, 3)
+	for env, cd := range deets {
+		r.NoError(cd.Finalize(), env)
+		r.Equal("ydb", cd.Dialect, env)
+		r.Equal("foo_"+env, cd.Database, env)
+	}
+}
+
 func Test_New_No_Dialect(t *testing.T) {
 	r := require.New(t)
 
@@ -0,0 +1,69 @@
+package caudit
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/gobuffalo/fizz"
+	"github.com/gobuffalo/genny/v2"
+)
+
+// New creates a generator to make the migration files of an audit table,
+// with the columns pop writes the audit entries of models to.
+func New(opts *Options) (*genny.Generator, error) {
+	g := genny.New()
+
+	if err := opts.Validate(); err != nil {
+		return g, err
+	}
+
+	t := fizz.NewTable(opts.AuditTable(), nil)
+	cols := []struct {
+		name    string
+		colType string
+		options fizz.Options
+	}{
+		{"id", "integer", fizz.Options{"primary": true}},
+		{"table_name", "string", fizz.Options{}},
+		{"record_id", "string", fizz.Options{}},
+		{"operation", "string", fizz.Options{}},
+		{"old_values", "text", fizz.Options{"null": true}},
+		{"new_values", "text", fizz.Options{"null": true}},
+		{"actor", "string", fizz.Options{"null": true}},
+	}
+	for _, c := range cols {
+		if err := t.Column(c.name, c.colType, c.options); err != nil {
+			return g, err
+		}
+	}
+	if err := t.Index([]string{"table_name", "record_id"}, fizz.Options{}); err != nil {
+		return g, err
+	}
+
+	up := t.Fizz()
+	down := t.UnFizz()
+	if opts.Type == "sql" {
+		type nameable interface {
+			Name() string
+		}
+		translatorNameable, ok := opts.Translator.(nameable)
+		if !ok {
+			return g, errors.New("fizz translator needs a Name method")
+		}
+		m, err := fizz.AString(up, opts.Translator)
+		if err != nil {
+			return g, err
+		}
+		g.File(genny.NewFileS(filepath.Join(opts.Path, fmt.Sprintf("%s.%s.up.sql", opts.Name, translatorNameable.Name())), m))
+		m, err = fizz.AString(down, opts.Translator)
+		if err != nil {
+			return g, err
+		}
+		g.File(genny.NewFileS(filepath.Join(opts.Path, fmt.Sprintf("%s.%s.down.sql", opts.Name, translatorNameable.Name())), m))
+		return g, nil
+	}
+	g.File(genny.NewFileS(filepath.Join(opts.Path, opts.Name+".up.fizz"), up))
+	g.File(genny.NewFileS(filepath.Join(opts.Path, opts.Name+".down.fizz"), down))
+	return g, nil
+}
@@ -0,0 +1,98 @@
+package caudit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/genny/v2/gentest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	r := require.New(t)
+
+	t0, _ := time.Parse(time.RFC3339, "2019-08-28T07:46:02Z")
+	nowFunc = func() time.Time { return t0 }
+	defer func() { nowFunc = time.Now }()
+
+	table := `create_table("%[1]s") {
+	t.Column("id", "integer", {primary: true})
+	t.Column("table_name", "string", {})
+	t.Column("record_id", "string", {})
+	t.Column("operation", "string", {})
+	t.Column("old_values", "text", {null: true})
+	t.Column("new_values", "text", {null: true})
+	t.Column("actor", "string", {null: true})
+	t.Timestamps()
+	t.Index(["table_name", "record_id"], {name: "%[1]s_table_name_record_id_idx"})
+}`
+
+	cases := []struct {
+		Options *Options
+		Name    string
+		Table   string
+	}{
+		{&Options{TableName: "widget"}, "20190828074602_create_widgets_history", "widgets_history"},
+		{&Options{}, "20190828074602_create_audit_log", "audit_log"},
+		{&Options{TableName: "widgets", Name: "widgets_audit"}, "widgets_audit", "widgets_history"},
+	}
+
+	for _, c := range cases {
+		g, err := New(c.Options)
+		r.NoError(err)
+
+		run := gentest.NewRunner()
+		run.With(g)
+		r.NoError(run.Run())
+
+		res := run.Results()
+		r.Len(res.Commands, 0)
+		r.Len(res.Files, 2)
+
+		f := res.Files[0]
+		r.Equal("migrations/"+c.Name+".down.fizz", f.Name())
+		r.Equal(`drop_table("`+c.Table+`")`, f.String())
+
+		f = res.Files[1]
+		r.Equal("migrations/"+c.Name+".up.fizz", f.Name())
+		r.Equal(fmt.Sprintf(table, c.Table), f.String())
+	}
+}
+
+func Test_New_SQL(t *testing.T) {
+	r := require.New(t)
+
+	g, err := New(&Options{
+		TableName:  "widgets",
+		Name:       "create_widgets_history",
+		Type:       "sql",
+		Translator: mockTranslator{},
+	})
+	r.NoError(err)
+
+	run := gentest.NewRunner()
+	run.With(g)
+	r.NoError(run.Run())
+
+	res := run.Results()
+	r.Len(res.Files, 2)
+
+	f := res.Files[0]
+	r.Equal("migrations/create_widgets_history.test.down.sql", f.Name())
+	r.Equal("drop table;", f.String())
+
+	f = res.Files[1]
+	r.Equal("migrations/create_widgets_history.test.up.sql", f.Name())
+	r.Contains(f.String(), "create table;")
+}
+
+func Test_Options_Validate(t *testing.T) {
+	r := require.New(t)
+
+	opts := &Options{Type: "sql"}
+	r.EqualError(opts.Validate(), "sql migrations require a fizz translator")
+
+	opts = &Options{Type: "xml"}
+	r.EqualError(opts.Validate(), "xml migration type is not allowed")
+}
@@ -0,0 +1,62 @@
+package caudit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/fizz"
+	"github.com/gobuffalo/flect/name"
+	"github.com/gobuffalo/pop/v6"
+)
+
+var nowFunc = time.Now
+
+// Options for the audit table generator.
+type Options struct {
+	// TableName is the table of the audited model, whose history table is
+	// named <table>_history. The shared audit_log table is created if it's
+	// empty.
+	TableName string
+	// Name is the name of the generated file.
+	Name string
+	// Path is the dir path where to generate the migration files.
+	Path string
+	// Translator is a Fizz translator to use when asking for SQL migrations.
+	Translator fizz.Translator
+	// Type is the type of migration to generate (sql or fizz).
+	// For sql migrations, you'll have to provide a valid Translator too.
+	Type string
+}
+
+// AuditTable returns the name of the audit table to create.
+func (opts *Options) AuditTable() string {
+	if len(opts.TableName) == 0 {
+		return pop.AuditLogTable
+	}
+	return opts.TableName + "_history"
+}
+
+// Validate that options are usable
+func (opts *Options) Validate() error {
+	if len(opts.TableName) > 0 {
+		opts.TableName = name.New(opts.TableName).Tableize().String()
+	}
+	if len(opts.Path) == 0 {
+		opts.Path = "migrations"
+	}
+	if len(opts.Name) == 0 {
+		timestamp := nowFunc().UTC().Format("20060102150405")
+		opts.Name = fmt.Sprintf("%s_create_%s", timestamp, opts.AuditTable())
+	}
+	if len(opts.Type) == 0 {
+		opts.Type = "fizz"
+	}
+	if opts.Type != "fizz" && opts.Type != "sql" {
+		return fmt.Errorf("%s migration type is not allowed", opts.Type)
+	}
+	if opts.Type == "sql" && opts.Translator == nil {
+		return errors.New("sql migrations require a fizz translator")
+	}
+	return nil
+}
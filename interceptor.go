@@ -0,0 +1,300 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/gobuffalo/pop/v6/logging"
+	"github.com/jmoiron/sqlx"
+)
+
+// Statement is a statement on its way to the database, as seen by the
+// interceptors of a connection.
+type Statement struct {
+	// Operation is the store method running the statement: "Select", "Get",
+	// "Exec", "NamedExec", "NamedQuery" or "Query".
+	Operation string
+	// Model is the destination of Select and Get, or the argument of
+	// NamedExec and NamedQuery. It is nil for the other operations.
+	Model interface{}
+	// SQL is the statement, with the bindvars of the dialect.
+	SQL string
+	// Args are the arguments of the statement. Named statements take their
+	// arguments from the Model instead.
+	Args []interface{}
+	// Context is the context the statement runs with. Interceptors may
+	// replace it, such as to carry a tracing span to the driver.
+	Context context.Context
+	// ConnectionID is the ID of the connection running the statement.
+	ConnectionID string
+	// TxID is the ID of the transaction running the statement, 0 outside of
+	// one.
+	TxID int
+	// RowsAffected is the number of rows affected by Exec and NamedExec, set
+	// once they ran. It is negative for the other operations, or if the driver
+	// doesn't report it.
+	RowsAffected int64
+}
+
+// Interceptor wraps the execution of the statements of a connection. It
+// calls next to run the statement, and may change the SQL or arguments of the
+// statement beforehand, veto it by returning an error without calling next,
+// or observe the duration and error of next.
+//
+//	c.Use(func(stmt *pop.Statement, next func(*pop.Statement) error) error {
+//		start := time.Now()
+//		err := next(stmt)
+//		metrics.Observe(stmt.Operation, time.Since(start), err)
+//		return err
+//	})
+type Interceptor func(stmt *Statement, next func(*Statement) error) error
+
+// Use adds interceptors to the connection. They wrap every statement run on
+// the connection, its copies and its transactions, in the order they were
+// added: the first one added is the outermost. Interceptors added to a copy or
+// a transaction only apply to it and its own copies and transactions, inside
+// those of the connection it comes from.
+func (c *Connection) Use(interceptors ...Interceptor) {
+	c.ownInterceptors().add(interceptors...)
+}
+
+// ownInterceptors returns the interceptor chain of the connection, after
+// giving it its own if it is a copy or a transaction still sharing the chain
+// of its parent.
+func (c *Connection) ownInterceptors() *interceptorChain {
+	if c.interceptors == nil || c.interceptors.owner != c {
+		c.interceptors = &interceptorChain{parent: c.interceptors, owner: c}
+		if c.Store != nil {
+			c.Store = c.interceptors.rewrap(c.Store, c.ID, c.txID())
+		}
+	}
+	return c.interceptors
+}
+
+// interceptorChain holds the interceptors and the logger of a connection,
+// shared by its copies and its transactions until they add their own, which
+// go to a new chain running after those of its parent.
+type interceptorChain struct {
+	mu     sync.RWMutex
+	list   []Interceptor
+	logger logging.Logger
+	parent *interceptorChain
+	owner  *Connection
+}
+
+func (ic *interceptorChain) add(interceptors ...Interceptor) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	ic.list = append(ic.list, interceptors...)
+}
+
+// all returns the interceptors of the chain, after those of its parents.
+func (ic *interceptorChain) all() []Interceptor {
+	if ic == nil {
+		return nil
+	}
+	ic.mu.RLock()
+	list := ic.list
+	ic.mu.RUnlock()
+	if ic.parent == nil {
+		return list
+	}
+	parent := ic.parent.all()
+	return append(parent[:len(parent):len(parent)], list...)
+}
+
+// wrap returns the store of the connection and transaction with the given
+// IDs, running its statements through the interceptors. A nil chain leaves the
+// store untouched.
+func (ic *interceptorChain) wrap(s Store, connID string, txID int) Store {
+	if ic == nil {
+		return s
+	}
+	return interceptedStore{Store: s, chain: ic, connID: connID, txID: txID}
+}
+
+func (ic *interceptorChain) setLogger(l logging.Logger) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	ic.logger = l
+}
+
+// currentLogger returns the logger of the chain, or else of its parents. It
+// is nil without a logger, for which the global logger is used.
+func (ic *interceptorChain) currentLogger() logging.Logger {
+	for ; ic != nil; ic = ic.parent {
+		ic.mu.RLock()
+		l := ic.logger
+		ic.mu.RUnlock()
+		if l != nil {
+			return l
+		}
+	}
+	return nil
+}
+
+// rewrap returns the store running its statements through the chain instead
+// of the one it was wrapped with, or wraps it if it had none.
+func (ic *interceptorChain) rewrap(s Store, connID string, txID int) Store {
+	switch t := s.(type) {
+	case contextStore:
+		t.Store = ic.rewrap(t.Store, connID, txID)
+		return t
+	case interceptedStore:
+		t.chain = ic
+		return t
+	}
+	return ic.wrap(s, connID, txID)
+}
+
+// run runs the statement through the interceptors, ending with exec, which
+// is logged to the current logger.
+func (ic *interceptorChain) run(stmt *Statement, exec func(*Statement) error) error {
+	list := ic.all()
+	if l := ic.currentLogger(); l != nil {
+		exec = logStatement(l, exec)
+	}
+
+	var next func(i int) func(*Statement) error
+	next = func(i int) func(*Statement) error {
+		if i == len(list) {
+			return exec
+		}
+		return func(s *Statement) error {
+			return list[i](s, next(i+1))
+		}
+	}
+	return next(0)(stmt)
+}
+
+// interceptedStore runs the statements of a store through interceptors.
+type interceptedStore struct {
+	Store
+	chain  *interceptorChain
+	connID string
+	txID   int
+}
+
+func (s interceptedStore) statement(ctx context.Context, op string, model interface{}, query string, args []interface{}) *Statement {
+	return &Statement{
+		Operation:    op,
+		Model:        model,
+		SQL:          query,
+		Args:         args,
+		Context:      ctx,
+		ConnectionID: s.connID,
+		TxID:         s.txID,
+		RowsAffected: -1,
+	}
+}
+
+// setRowsAffected records the rows affected by an Exec in the statement.
+func setRowsAffected(stmt *Statement, res sql.Result) {
+	if res == nil {
+		return
+	}
+	if n, err := res.RowsAffected(); err == nil {
+		stmt.RowsAffected = n
+	}
+}
+
+// Context returns the context of the wrapped store, if any.
+func (s interceptedStore) Context() context.Context {
+	if cs, ok := s.Store.(interface{ Context() context.Context }); ok {
+		return cs.Context()
+	}
+	return context.Background()
+}
+
+func (s interceptedStore) Select(dest interface{}, query string, args ...interface{}) error {
+	return s.chain.run(s.statement(s.Context(), "Select", dest, query, args), func(stmt *Statement) error {
+		return s.Store.SelectContext(stmt.Context, dest, stmt.SQL, stmt.Args...)
+	})
+}
+
+func (s interceptedStore) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.chain.run(s.statement(ctx, "Select", dest, query, args), func(stmt *Statement) error {
+		return s.Store.SelectContext(stmt.Context, dest, stmt.SQL, stmt.Args...)
+	})
+}
+
+func (s interceptedStore) Get(dest interface{}, query string, args ...interface{}) error {
+	return s.chain.run(s.statement(s.Context(), "Get", dest, query, args), func(stmt *Statement) error {
+		return s.Store.GetContext(stmt.Context, dest, stmt.SQL, stmt.Args...)
+	})
+}
+
+func (s interceptedStore) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.chain.run(s.statement(ctx, "Get", dest, query, args), func(stmt *Statement) error {
+		return s.Store.GetContext(stmt.Context, dest, stmt.SQL, stmt.Args...)
+	})
+}
+
+func (s interceptedStore) Exec(query string, args ...interface{}) (res sql.Result, err error) {
+	err = s.chain.run(s.statement(s.Context(), "Exec", nil, query, args), func(stmt *Statement) error {
+		res, err = s.Store.ExecContext(stmt.Context, stmt.SQL, stmt.Args...)
+		setRowsAffected(stmt, res)
+		return err
+	})
+	return res, err
+}
+
+func (s interceptedStore) ExecContext(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
+	err = s.chain.run(s.statement(ctx, "Exec", nil, query, args), func(stmt *Statement) error {
+		res, err = s.Store.ExecContext(stmt.Context, stmt.SQL, stmt.Args...)
+		setRowsAffected(stmt, res)
+		return err
+	})
+	return res, err
+}
+
+func (s interceptedStore) NamedExec(query string, arg interface{}) (res sql.Result, err error) {
+	err = s.chain.run(s.statement(s.Context(), "NamedExec", arg, query, nil), func(stmt *Statement) error {
+		res, err = s.Store.NamedExecContext(stmt.Context, stmt.SQL, arg)
+		setRowsAffected(stmt, res)
+		return err
+	})
+	return res, err
+}
+
+func (s interceptedStore) NamedExecContext(ctx context.Context, query string, arg interface{}) (res sql.Result, err error) {
+	err = s.chain.run(s.statement(ctx, "NamedExec", arg, query, nil), func(stmt *Statement) error {
+		res, err = s.Store.NamedExecContext(stmt.Context, stmt.SQL, arg)
+		setRowsAffected(stmt, res)
+		return err
+	})
+	return res, err
+}
+
+func (s interceptedStore) NamedQuery(query string, arg interface{}) (rows *sqlx.Rows, err error) {
+	err = s.chain.run(s.statement(s.Context(), "NamedQuery", arg, query, nil), func(stmt *Statement) error {
+		rows, err = s.Store.NamedQueryContext(stmt.Context, stmt.SQL, arg)
+		return err
+	})
+	return rows, err
+}
+
+func (s interceptedStore) NamedQueryContext(ctx context.Context, query string, arg interface{}) (rows *sqlx.Rows, err error) {
+	err = s.chain.run(s.statement(ctx, "NamedQuery", arg, query, nil), func(stmt *Statement) error {
+		rows, err = s.Store.NamedQueryContext(stmt.Context, stmt.SQL, arg)
+		return err
+	})
+	return rows, err
+}
+
+func (s interceptedStore) Queryx(query string, args ...interface{}) (rows *sqlx.Rows, err error) {
+	err = s.chain.run(s.statement(s.Context(), "Query", nil, query, args), func(stmt *Statement) error {
+		rows, err = s.Store.QueryxContext(stmt.Context, stmt.SQL, stmt.Args...)
+		return err
+	})
+	return rows, err
+}
+
+func (s interceptedStore) QueryxContext(ctx context.Context, query string, args ...interface{}) (rows *sqlx.Rows, err error) {
+	err = s.chain.run(s.statement(ctx, "Query", nil, query, args), func(stmt *Statement) error {
+		rows, err = s.Store.QueryxContext(stmt.Context, stmt.SQL, stmt.Args...)
+		return err
+	})
+	return rows, err
+}
@@ -0,0 +1,148 @@
+package pop
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_InterceptorChain_Order(t *testing.T) {
+	r := require.New(t)
+
+	calls := []string{}
+	ic := &interceptorChain{}
+	ic.add(func(stmt *Statement, next func(*Statement) error) error {
+		calls = append(calls, "outer")
+		stmt.SQL += " /* outer */"
+		return next(stmt)
+	}, func(stmt *Statement, next func(*Statement) error) error {
+		calls = append(calls, "inner")
+		err := next(stmt)
+		calls = append(calls, "inner done")
+		return err
+	})
+
+	err := ic.run(&Statement{SQL: "SELECT 1"}, func(stmt *Statement) error {
+		calls = append(calls, stmt.SQL)
+		return nil
+	})
+	r.NoError(err)
+	r.Equal([]string{"outer", "inner", "SELECT 1 /* outer */", "inner done"}, calls)
+}
+
+func Test_Connection_Use(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		stmts := []Statement{}
+		tx.Use(func(stmt *Statement, next func(*Statement) error) error {
+			stmts = append(stmts, *stmt)
+			return next(stmt)
+		})
+
+		user := User{Name: nulls.NewString("Mark")}
+		r.NoError(tx.Create(&user))
+		r.NotEmpty(stmts)
+		r.True(strings.HasPrefix(stmts[0].SQL, "INSERT INTO"), stmts[0].SQL)
+		r.NotNil(stmts[0].Context)
+
+		stmts = stmts[:0]
+		r.NoError(tx.Find(&User{}, user.ID))
+		r.Len(stmts, 1)
+		r.Equal("Get", stmts[0].Operation)
+		r.IsType(&User{}, stmts[0].Model)
+		r.Equal([]interface{}{user.ID}, stmts[0].Args)
+
+		// copies of the connection share its interceptors
+		stmts = stmts[:0]
+		r.NoError(tx.WithContext(tx.Context()).Where("id = ?", user.ID).All(&[]User{}))
+		r.Len(stmts, 1)
+		r.Equal("Select", stmts[0].Operation)
+
+		errVeto := errors.New("veto")
+		tx.Use(func(stmt *Statement, next func(*Statement) error) error {
+			if strings.HasPrefix(stmt.SQL, "DELETE") {
+				return errVeto
+			}
+			return next(stmt)
+		})
+		r.ErrorIs(tx.Destroy(&user), errVeto)
+		r.NoError(tx.Reload(&user))
+	})
+}
+
+func Test_Connection_Use_Rewrite(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		r.NoError(tx.Create(&User{Name: nulls.NewString("Mark")}))
+		r.NoError(tx.Create(&User{Name: nulls.NewString("Paul")}))
+
+		tx.Use(func(stmt *Statement, next func(*Statement) error) error {
+			if stmt.Operation == "Select" {
+				stmt.SQL = strings.Replace(stmt.SQL, "FROM users AS users", "FROM users AS users WHERE users.name = 'Paul'", 1)
+			}
+			return next(stmt)
+		})
+
+		users := []User{}
+		r.NoError(tx.All(&users))
+		r.Len(users, 1)
+		r.Equal("Paul", users[0].Name.String)
+	})
+}
+
+func Test_Connection_Use_CopyOnWrite(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	counter := func(n *int) Interceptor {
+		return func(stmt *Statement, next func(*Statement) error) error {
+			*n++
+			return next(stmt)
+		}
+	}
+
+	c := PDB.WithContext(PDB.Context())
+	outer, inner := 0, 0
+	c.Use(counter(&outer))
+	err := c.Rollback(func(tx *Connection) {
+		tx.Use(counter(&inner))
+		_, err := tx.Count(&User{})
+		r.NoError(err)
+	})
+	r.NoError(err)
+	r.Equal(1, outer)
+	r.Equal(1, inner)
+
+	// the interceptor of the transaction didn't leak into the connection
+	_, err = c.Count(&User{})
+	r.NoError(err)
+	r.Equal(2, outer)
+	r.Equal(1, inner)
+
+	// nor did the one of the copy into the pool
+	_, err = PDB.Count(&User{})
+	r.NoError(err)
+	r.Equal(2, outer)
+
+	// copies run the interceptors added to the connection later
+	cp := c.WithContext(c.Context())
+	later := 0
+	c.Use(counter(&later))
+	_, err = cp.Count(&User{})
+	r.NoError(err)
+	r.Equal(3, outer)
+	r.Equal(1, later)
+}
@@ -0,0 +1,197 @@
+package pop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gobuffalo/pop/v6/logging"
+	"github.com/jmoiron/sqlx"
+)
+
+// Iterator streams the rows matched by a query one at a time, instead of
+// loading all of them into a slice like All. It must be closed when done.
+type Iterator struct {
+	conn *Connection
+	rows *sqlx.Rows
+	ctx  context.Context
+	err  error
+}
+
+// Iterate runs the query for the given model and returns an Iterator over
+// the matched rows. Associations are not loaded.
+//
+//	it, err := c.Where("active = ?", true).Iterate(&User{})
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//	for it.Next() {
+//		u := User{}
+//		if err := it.Scan(&u); err != nil {
+//			return err
+//		}
+//	}
+//	return it.Err()
+func (c *Connection) Iterate(model interface{}) (*Iterator, error) {
+	return Q(c).Iterate(model)
+}
+
+// Iterate runs the query for the given model and returns an Iterator over
+// the matched rows. Associations are not loaded.
+//
+//	it, err := q.Where("active = ?", true).Iterate(&User{})
+func (q *Query) Iterate(model interface{}) (*Iterator, error) {
+	if err := q.checkClauses(); err != nil {
+		return nil, err
+	}
+
+	m := NewModel(model, q.Connection.Context())
+	sqlQuery, args := q.ToSQL(m)
+	q.Connection.log(logging.SQL, sqlQuery, args...)
+	rows, err := q.Connection.readStore().QueryxContext(m.ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &Iterator{conn: q.Connection, rows: rows, ctx: m.ctx}, nil
+}
+
+// Next prepares the next row for Scan. It returns false when there are no
+// more rows, an error occurred or the context of the connection is done.
+func (it *Iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+	return it.rows.Next()
+}
+
+// Scan copies the current row into model, which must be a pointer to a
+// struct, and runs its AfterFind callback.
+func (it *Iterator) Scan(model interface{}) error {
+	if err := it.rows.StructScan(model); err != nil {
+		return err
+	}
+	return NewModel(model, it.ctx).afterFind(it.conn, false)
+}
+
+// Err returns the error which ended the iteration, if any.
+func (it *Iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.rows.Err()
+}
+
+// Close releases the rows of the iterator.
+func (it *Iterator) Close() error {
+	return it.rows.Close()
+}
+
+// Each runs fn for every row matched by the query, streaming the rows from
+// the database instead of loading all of them into memory. model must be a
+// pointer to a struct; fn receives a new value of the same type for every row,
+// after its AfterFind callback ran. Associations are not loaded. Iteration
+// stops at the first error returned by fn, or when the context of the
+// connection is done.
+//
+// With WithBatchSize, rows are selected in batches ordered by ID, each batch
+// starting after the last ID of the previous one. This avoids long-lived
+// cursors, and allows fn to run other queries on drivers which do not support
+// them while rows are being streamed. Batches can not be combined with Order,
+// Limit, Paginate or RawQuery.
+//
+//	c.Where("active = ?", true).Each(&User{}, func(m interface{}) error {
+//		u := m.(*User)
+//		return export(u)
+//	}, pop.WithBatchSize(1000))
+func (c *Connection) Each(model interface{}, fn func(m interface{}) error, opts ...BatchOption) error {
+	return Q(c).Each(model, fn, opts...)
+}
+
+// Each runs fn for every row matched by the query, streaming the rows from
+// the database instead of loading all of them into memory. See
+// Connection.Each for details.
+func (q *Query) Each(model interface{}, fn func(m interface{}) error, opts ...BatchOption) error {
+	o := batchOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	t := reflect.TypeOf(model)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Each expects a pointer to a struct, got %T", model)
+	}
+
+	return q.Connection.timeFunc("Each", func() error {
+		if o.size == 0 {
+			_, err := q.each(model, fn)
+			return err
+		}
+		return q.eachBatch(model, fn, o.size)
+	})
+}
+
+// each streams the rows matched by the query and returns the number of rows
+// passed to fn.
+func (q *Query) each(model interface{}, fn func(m interface{}) error) (int, error) {
+	it, err := q.Iterate(model)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+
+	t := reflect.TypeOf(model).Elem()
+	n := 0
+	for it.Next() {
+		v := reflect.New(t).Interface()
+		if err := it.Scan(v); err != nil {
+			return n, err
+		}
+		n++
+		if err := fn(v); err != nil {
+			return n, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return n, err
+	}
+	return n, it.Close()
+}
+
+// eachBatch runs each for consecutive batches of rows, using the last ID of a
+// batch as the lower bound of the next one.
+func (q *Query) eachBatch(model interface{}, fn func(m interface{}) error, size int) error {
+	if q.RawSQL.Fragment != "" || len(q.orderClauses) > 0 || q.limitResults > 0 || q.Paginator != nil {
+		return errors.New("batched Each can not be combined with Order, Limit, Paginate or RawQuery")
+	}
+
+	m := NewModel(model, q.Connection.Context())
+	idColumn := fmt.Sprintf("%s.%s", m.Alias(), m.IDField())
+
+	var last interface{}
+	for {
+		bq := Q(q.Connection)
+		q.Clone(bq)
+		if last != nil {
+			bq.Where(fmt.Sprintf("%s > ?", idColumn), last)
+		}
+		bq.Order(fmt.Sprintf("%s ASC", idColumn))
+		bq.Limit(size)
+
+		n, err := bq.each(model, func(v interface{}) error {
+			last = NewModel(v, m.ctx).ID()
+			return fn(v)
+		})
+		if err != nil {
+			return err
+		}
+		if n < size {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package pop
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Each(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		users := CallbacksUsers{{}, {}, {}, {}, {}}
+		r.NoError(tx.Create(&users))
+
+		found := []*CallbacksUser{}
+		err := tx.Order("id asc").Each(&CallbacksUser{}, func(m interface{}) error {
+			u := m.(*CallbacksUser)
+			r.Equal("AfterFind", u.AfterF)
+			found = append(found, u)
+			return nil
+		})
+		r.NoError(err)
+		r.Len(found, 5)
+		for i := range users {
+			r.Equal(users[i].ID, found[i].ID)
+		}
+
+		errStop := errors.New("stop")
+		n := 0
+		err = tx.Each(&CallbacksUser{}, func(m interface{}) error {
+			n++
+			return errStop
+		})
+		r.ErrorIs(err, errStop)
+		r.Equal(1, n)
+
+		r.Error(tx.Each(CallbacksUser{}, func(m interface{}) error { return nil }))
+	})
+}
+
+func Test_Each_Batches(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		users := CallbacksUsers{{}, {}, {}, {}, {}}
+		r.NoError(tx.Create(&users))
+
+		ids := []int{}
+		err := tx.Where("id >= ?", users[1].ID).Each(&CallbacksUser{}, func(m interface{}) error {
+			ids = append(ids, m.(*CallbacksUser).ID)
+			return nil
+		}, WithBatchSize(2))
+		r.NoError(err)
+		r.Equal([]int{users[1].ID, users[2].ID, users[3].ID, users[4].ID}, ids)
+
+		r.Error(tx.Order("id desc").Each(&CallbacksUser{}, func(m interface{}) error { return nil }, WithBatchSize(2)))
+	})
+}
+
+func Test_Each_Context(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		users := CallbacksUsers{{}, {}, {}}
+		r.NoError(tx.Create(&users))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		n := 0
+		err := tx.WithContext(ctx).Each(&CallbacksUser{}, func(m interface{}) error {
+			n++
+			cancel()
+			return nil
+		})
+		r.ErrorIs(err, context.Canceled)
+		r.Equal(1, n)
+	})
+}
+
+func Test_Iterate(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		users := CallbacksUsers{{}, {}}
+		r.NoError(tx.Create(&users))
+
+		it, err := tx.Order("id asc").Iterate(&CallbacksUser{})
+		r.NoError(err)
+		defer it.Close()
+
+		found := CallbacksUsers{}
+		for it.Next() {
+			u := CallbacksUser{}
+			r.NoError(it.Scan(&u))
+			r.Equal("AfterFind", u.AfterF)
+			found = append(found, u)
+		}
+		r.NoError(it.Err())
+		r.NoError(it.Close())
+		r.Len(found, 2)
+		r.Equal(users[0].ID, found[0].ID)
+	})
+}
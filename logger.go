@@ -4,15 +4,16 @@ import (
 	"fmt"
 	stdlog "log"
 	"os"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/gobuffalo/pop/v6/logging"
 )
 
-// Debug mode, to toggle verbose log traces
+// Debug mode, to toggle verbose log traces of the global logger
 var Debug = false
 
-// Color mode, to toggle colored logs
+// Color mode, to toggle colored logs of the global logger
 var Color = true
 
 // SetLogger overrides the default logger.
@@ -64,7 +65,7 @@ var txlog = func(lvl logging.Level, anon interface{}, s string, args ...interfac
 			extra = printStats(&typed.Store)
 		case *Tx:
 			txID = typed.ID
-		case store:
+		case Store:
 			if t, ok := typed.(*Tx); ok {
 				txID = t.ID
 			}
@@ -85,11 +86,91 @@ var txlog = func(lvl logging.Level, anon interface{}, s string, args ...interfac
 	defaultStdLogger.Println(s)
 }
 
+// UseLogger sends the log entries of the connection, its copies and its
+// transactions to the logger, instead of the global logger set by SetLogger
+// and SetTxLogger. The logger receives every entry regardless of Debug, and
+// the SQL entries once the statement ran, with its duration, rows affected
+// and error. Calling it again replaces the logger. On a copy or a
+// transaction, it only applies to them and their own copies and transactions.
+// Set it when setting up the connection, or use ConnectionDetails.Logger.
+//
+//	c.UseLogger(logging.NewSlogLogger(slog.Default()))
+func (c *Connection) UseLogger(l logging.Logger) {
+	c.ownInterceptors().setLogger(l)
+}
+
+// logStatement wraps exec to log the statement to the logger once it ran,
+// after the interceptors changed it.
+func logStatement(l logging.Logger, exec func(*Statement) error) func(*Statement) error {
+	return func(stmt *Statement) error {
+		start := time.Now()
+		err := exec(stmt)
+		l.Log(logging.Entry{
+			Level:        logging.SQL,
+			Message:      stmt.SQL,
+			Args:         stmt.Args,
+			ConnectionID: stmt.ConnectionID,
+			TxID:         stmt.TxID,
+			Duration:     time.Since(start),
+			RowsAffected: stmt.RowsAffected,
+			Err:          err,
+			Context:      stmt.Context,
+		})
+		return err
+	}
+}
+
+// log logs to the logger of the connection, or to the global txlog. The
+// logger of the connection gets the statements once they ran instead, see
+// UseLogger.
+func (c *Connection) log(lvl logging.Level, s string, args ...interface{}) {
+	l := c.interceptors.currentLogger()
+	if l == nil {
+		txlog(lvl, c, s, args...)
+		return
+	}
+	if lvl == logging.SQL {
+		return
+	}
+	l.Log(c.logEntry(lvl, fmt.Sprintf(s, args...)))
+}
+
+// logDirect logs a statement which doesn't run through the store of the
+// connection, such as the beginning or the end of a transaction.
+func (c *Connection) logDirect(s string, args ...interface{}) {
+	l := c.interceptors.currentLogger()
+	if l == nil {
+		txlog(logging.SQL, c, s, args...)
+		return
+	}
+	e := c.logEntry(logging.SQL, s)
+	e.Args = args
+	l.Log(e)
+}
+
+func (c *Connection) logEntry(lvl logging.Level, msg string) logging.Entry {
+	return logging.Entry{
+		Level:        lvl,
+		Message:      msg,
+		ConnectionID: c.ID,
+		TxID:         c.txID(),
+		RowsAffected: -1,
+		Context:      c.Context(),
+	}
+}
+
+// txID returns the ID of the transaction of the connection, 0 outside of one.
+func (c *Connection) txID() int {
+	if c.TX != nil {
+		return c.TX.ID
+	}
+	return 0
+}
+
 // printStats returns a string represent connection pool information from
 // the given store.
-func printStats(s *store) string {
-	if db, ok := (*s).(*dB); ok {
-		s := db.Stats()
+func printStats(s *Store) string {
+	if s, ok := storeStats(*s); ok {
 		return fmt.Sprintf(", maxconn: %d, openconn: %d, in-use: %d, idle: %d", s.MaxOpenConnections, s.OpenConnections, s.InUse, s.Idle)
 	}
 
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"context"
+	"time"
+)
+
+// Entry is a log entry of a connection.
+type Entry struct {
+	// Level of the entry.
+	Level Level
+	// Message of the entry, the statement for SQL entries.
+	Message string
+	// Args are the arguments of the statement of SQL entries.
+	Args []interface{}
+	// ConnectionID is the ID of the connection logging the entry.
+	ConnectionID string
+	// TxID is the ID of the transaction of the connection, 0 outside of one.
+	TxID int
+	// Duration is the time the statement of SQL entries took.
+	Duration time.Duration
+	// RowsAffected is the number of rows affected by the statement of SQL
+	// entries, negative when unknown.
+	RowsAffected int64
+	// Err is the error of the statement of SQL entries, if it failed.
+	Err error
+	// Context is the context of the statement of SQL entries, to let the
+	// logger add the values it carries, such as a trace ID.
+	Context context.Context
+}
+
+// Logger receives the log entries of a connection.
+type Logger interface {
+	Log(e Entry)
+}
+
+// LoggerFunc is a function used as a Logger.
+type LoggerFunc func(e Entry)
+
+// Log calls f(e).
+func (f LoggerFunc) Log(e Entry) {
+	f(e)
+}
@@ -0,0 +1,72 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LevelSQL is the slog level of the SQL entries, below slog.LevelDebug.
+const LevelSQL = slog.LevelDebug - 4
+
+// NewSlogLogger returns a Logger writing the entries to the slog logger, with
+// the fields of the entries as attributes. The handler of the slog logger
+// decides which levels are logged.
+//
+//	deets.Logger = logging.NewSlogLogger(slog.Default())
+func NewSlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l: l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Log(e Entry) {
+	ctx := e.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	level := SlogLevel(e.Level)
+	if !s.l.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, 6)
+	if e.ConnectionID != "" {
+		attrs = append(attrs, slog.String("conn", e.ConnectionID))
+	}
+	if e.TxID != 0 {
+		attrs = append(attrs, slog.Int("tx", e.TxID))
+	}
+	if e.Level == SQL {
+		attrs = append(attrs, slog.Duration("duration", e.Duration))
+		if e.RowsAffected >= 0 {
+			attrs = append(attrs, slog.Int64("rows_affected", e.RowsAffected))
+		}
+		if len(e.Args) > 0 {
+			attrs = append(attrs, slog.Any("args", e.Args))
+		}
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.Any("error", e.Err))
+	}
+	s.l.LogAttrs(ctx, level, e.Message, attrs...)
+}
+
+// SlogLevel returns the slog level of a level.
+func SlogLevel(l Level) slog.Level {
+	switch l {
+	case SQL:
+		return LevelSQL
+	case Debug:
+		return slog.LevelDebug
+	case Info:
+		return slog.LevelInfo
+	case Warn:
+		return slog.LevelWarn
+	}
+	return slog.LevelError
+}
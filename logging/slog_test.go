@@ -0,0 +1,60 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SlogLogger(t *testing.T) {
+	r := require.New(t)
+
+	bb := &bytes.Buffer{}
+	h := slog.NewTextHandler(bb, &slog.HandlerOptions{
+		Level: LevelSQL,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	l := NewSlogLogger(slog.New(h))
+
+	l.Log(Entry{
+		Level:        SQL,
+		Message:      "UPDATE users SET name = $1",
+		Args:         []interface{}{"Mark"},
+		ConnectionID: "tx-1",
+		TxID:         1,
+		Duration:     time.Millisecond,
+		RowsAffected: 2,
+	})
+	r.Equal(`level=DEBUG-4 msg="UPDATE users SET name = $1" conn=tx-1 tx=1 duration=1ms rows_affected=2 args=[Mark]`, strings.TrimSpace(bb.String()))
+
+	bb.Reset()
+	l.Log(Entry{Level: Warn, Message: "oops", ConnectionID: "conn-1", RowsAffected: -1, Err: errors.New("boom")})
+	r.Equal(`level=WARN msg=oops conn=conn-1 error=boom`, strings.TrimSpace(bb.String()))
+}
+
+func Test_SlogLogger_Level(t *testing.T) {
+	r := require.New(t)
+
+	bb := &bytes.Buffer{}
+	l := NewSlogLogger(slog.New(slog.NewTextHandler(bb, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	l.Log(Entry{Level: SQL, Message: "SELECT 1", RowsAffected: -1})
+	l.Log(Entry{Level: Debug, Message: "debug"})
+	r.Empty(bb.String())
+
+	l.Log(Entry{Level: Info, Message: "info"})
+	r.Contains(bb.String(), "msg=info")
+}
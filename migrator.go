@@ -36,7 +36,7 @@ type Migrator struct {
 	DownMigrations DownMigrations
 }
 
-func (m Migrator) migrationIsCompatible(d dialect, mi Migration) bool {
+func (m Migrator) migrationIsCompatible(d Dialect, mi Migration) bool {
 	if mi.DBType == "all" || mi.DBType == d.Name() {
 		return true
 	}
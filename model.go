@@ -2,6 +2,7 @@ package pop
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"reflect"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/gobuffalo/flect"
 	nflect "github.com/gobuffalo/flect/name"
+	"github.com/gobuffalo/nulls"
 	"github.com/gobuffalo/pop/v6/columns"
 	"github.com/gofrs/uuid"
 )
@@ -221,6 +223,61 @@ func (m *Model) setUpdatedAt(now time.Time) {
 	}
 }
 
+// softDeleteColumn returns the column which marks the model as deleted, or
+// an empty string if rows of the model are deleted for real.
+func (m *Model) softDeleteColumn() string {
+	_, column := columns.SoftDeleteFor(m.Value)
+	return column
+}
+
+// setDeletedAt marks the model as deleted at the given time, or as not
+// deleted for the zero time.
+func (m *Model) setDeletedAt(t time.Time) error {
+	field, _ := columns.SoftDeleteFor(m.Value)
+	fbn, err := m.fieldByName(field)
+	if err != nil {
+		return err
+	}
+	switch fbn.Interface().(type) {
+	case nulls.Time:
+		fbn.Set(reflect.ValueOf(nulls.Time{Time: t, Valid: !t.IsZero()}))
+	case sql.NullTime:
+		fbn.Set(reflect.ValueOf(sql.NullTime{Time: t, Valid: !t.IsZero()}))
+	case *time.Time:
+		if t.IsZero() {
+			fbn.Set(reflect.Zero(fbn.Type()))
+		} else {
+			fbn.Set(reflect.ValueOf(&t))
+		}
+	default:
+		return fmt.Errorf("soft delete field %s must be a nulls.Time, sql.NullTime or *time.Time, got %s", field, fbn.Type())
+	}
+	return nil
+}
+
+// versionColumn returns the optimistic locking column of the model, or an
+// empty string if updates of the model are not checked.
+func (m *Model) versionColumn() string {
+	_, column := columns.VersionFor(m.Value)
+	return column
+}
+
+// incrementVersion increments the optimistic locking version of the model.
+func (m *Model) incrementVersion() error {
+	field, _ := columns.VersionFor(m.Value)
+	fbn, err := m.fieldByName(field)
+	if err != nil {
+		return err
+	}
+	switch fbn.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fbn.SetInt(fbn.Int() + 1)
+	default:
+		return fmt.Errorf("version field %s must be an integer, got %s", field, fbn.Type())
+	}
+	return nil
+}
+
 func (m *Model) WhereID() string {
 	return fmt.Sprintf("%s.%s = ?", m.Alias(), m.IDField())
 }
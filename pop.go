@@ -1,6 +1,10 @@
 package pop
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // EagerMode type for all eager modes supported in pop.
 type EagerMode uint8
@@ -44,7 +48,42 @@ var urlParser = make(map[string]func(*ConnectionDetails) error)
 var finalizer = make(map[string]func(*ConnectionDetails))
 
 // map of connection creators
-var newConnection = make(map[string]func(*ConnectionDetails) (dialect, error))
+var newConnection = make(map[string]func(*ConnectionDetails) (Dialect, error))
+
+// RegisterDialect registers a new dialect under the given name, so it can
+// be used in `ConnectionDetails` and database.yml like the built-in ones.
+// The constructor is required; urlParser and finalizer are optional and can
+// be nil. urlParser is used to fill `ConnectionDetails` from a URL, and
+// finalizer to set dialect specific defaults.
+//
+// RegisterDialect is not safe for concurrent use and is meant to be called
+// from an `init()` function, as the built-in dialects do.
+//
+//	func init() {
+//		pop.RegisterDialect("clickhouse", newClickHouse, urlParserClickHouse, nil)
+//	}
+func RegisterDialect(name string, constructor func(*ConnectionDetails) (Dialect, error), urlParserFn func(*ConnectionDetails) error, finalizerFn func(*ConnectionDetails)) error {
+	name = strings.ToLower(name)
+	if name == "" {
+		return errors.New("dialect name must not be empty")
+	}
+	if constructor == nil {
+		return fmt.Errorf("dialect %s: constructor must not be nil", name)
+	}
+	if DialectSupported(name) {
+		return fmt.Errorf("dialect %s is already registered", name)
+	}
+
+	AvailableDialects = append(AvailableDialects, name)
+	newConnection[name] = constructor
+	if urlParserFn != nil {
+		urlParser[name] = urlParserFn
+	}
+	if finalizerFn != nil {
+		finalizer[name] = finalizerFn
+	}
+	return nil
+}
 
 // DialectSupported checks support for the given database dialect
 func DialectSupported(d string) bool {
@@ -283,6 +283,31 @@ type Composer struct {
 	UpdatedAt time.Time `db:"updated_at"`
 }
 
+type SoftAuthor struct {
+	ID        int        `db:"id"`
+	Name      string     `db:"name"`
+	Books     []SoftBook `has_many:"soft_books" order_by:"title asc"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+type SoftBook struct {
+	ID           int        `db:"id"`
+	Title        string     `db:"title"`
+	SoftAuthorID nulls.Int  `db:"soft_author_id"`
+	DeletedAt    nulls.Time `db:"deleted_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+type LockedBook struct {
+	ID          int       `db:"id"`
+	Title       string    `db:"title"`
+	LockVersion int       `db:"lock_version"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
 type Course struct {
 	ID        uuid.UUID `json:"id" db:"id"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
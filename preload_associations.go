@@ -8,7 +8,6 @@ import (
 
 	"github.com/gobuffalo/flect"
 	"github.com/gobuffalo/pop/v6/internal/defaults"
-	"github.com/gobuffalo/pop/v6/logging"
 	"github.com/jmoiron/sqlx"
 	"github.com/jmoiron/sqlx/reflectx"
 )
@@ -466,7 +465,7 @@ func preloadManyToMany(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMeta
 		return err
 	}
 
-	txlog(logging.SQL, cn, sql, args...)
+	tx.logDirect(sql, args...)
 	rows, err := cn.Queryx(sql, args...)
 	if err != nil {
 		return err
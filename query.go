@@ -3,6 +3,7 @@ package pop
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gobuffalo/pop/v6/logging"
 )
@@ -30,7 +31,17 @@ type Query struct {
 	joinClauses             joinClauses
 	groupClauses            groupClauses
 	havingClauses           havingClauses
+	unscoped                bool
+	onlyDeleted             bool
+	lock                    RowLock
+	withClauses             clauses
+	fromSubquery            *fromSubqueryClause
+	fromModel               interface{}
+	setClauses              setClauses
+	distinct                bool
+	distinctOn              []string
 	Paginator               *Paginator
+	CursorPaginator         *CursorPaginator
 	Connection              *Connection
 	Operation               operation
 }
@@ -50,6 +61,15 @@ func (q *Query) Clone(targetQ *Query) {
 	targetQ.groupClauses = q.groupClauses
 	targetQ.havingClauses = q.havingClauses
 	targetQ.addColumns = q.addColumns
+	targetQ.unscoped = q.unscoped
+	targetQ.onlyDeleted = q.onlyDeleted
+	targetQ.lock = q.lock
+	targetQ.withClauses = q.withClauses
+	targetQ.fromSubquery = q.fromSubquery
+	targetQ.fromModel = q.fromModel
+	targetQ.setClauses = q.setClauses
+	targetQ.distinct = q.distinct
+	targetQ.distinctOn = q.distinctOn
 	targetQ.Operation = q.Operation
 
 	if q.Paginator != nil {
@@ -57,6 +77,11 @@ func (q *Query) Clone(targetQ *Query) {
 		targetQ.Paginator = &paginator
 	}
 
+	if q.CursorPaginator != nil {
+		paginator := *q.CursorPaginator
+		targetQ.CursorPaginator = &paginator
+	}
+
 	if q.Connection != nil {
 		connection := *q.Connection
 		targetQ.Connection = &connection
@@ -197,6 +222,225 @@ func (q *Query) EagerPreload(fields ...string) *Query {
 	return q
 }
 
+// Unscoped includes soft deleted rows in the query, and makes Delete remove
+// rows for real.
+//
+//	c.Unscoped().All(&users)
+func (c *Connection) Unscoped() *Query {
+	return Q(c).Unscoped()
+}
+
+// Unscoped includes soft deleted rows in the query, and makes Delete remove
+// rows for real.
+//
+//	q.Unscoped().All(&users)
+func (q *Query) Unscoped() *Query {
+	q.unscoped = true
+	q.onlyDeleted = false
+	return q
+}
+
+// OnlyDeleted restricts the query to soft deleted rows. Delete removes the
+// matched rows for real.
+//
+//	c.OnlyDeleted().All(&users)
+func (c *Connection) OnlyDeleted() *Query {
+	return Q(c).OnlyDeleted()
+}
+
+// OnlyDeleted restricts the query to soft deleted rows. Delete removes the
+// matched rows for real.
+//
+//	q.OnlyDeleted().All(&users)
+func (q *Query) OnlyDeleted() *Query {
+	q.onlyDeleted = true
+	q.unscoped = false
+	return q
+}
+
+// The strengths and wait policies of a RowLock, which dialects turn into
+// their own locking clause.
+const (
+	LockUpdate     = "UPDATE"
+	LockShare      = "SHARE"
+	LockNoWait     = "NOWAIT"
+	LockSkipLocked = "SKIP LOCKED"
+)
+
+// RowLock is the row-level locking clause of a SELECT query.
+type RowLock struct {
+	// Strength is either LockUpdate or LockShare.
+	Strength string
+	// Wait is either empty, LockNoWait or LockSkipLocked.
+	Wait string
+}
+
+// ForUpdate locks the selected rows against concurrent updates until the end
+// of the transaction. It requires a transaction and is not supported by
+// SQLite.
+//
+//	tx.ForUpdate().SkipLocked().Where("state = ?", "queued").First(&job)
+func (c *Connection) ForUpdate() *Query {
+	return Q(c).ForUpdate()
+}
+
+// ForUpdate locks the selected rows against concurrent updates until the end
+// of the transaction. It requires a transaction and is not supported by
+// SQLite.
+//
+//	q.ForUpdate().SkipLocked().First(&job)
+func (q *Query) ForUpdate() *Query {
+	q.lock.Strength = LockUpdate
+	return q
+}
+
+// ForShare locks the selected rows against concurrent updates, while still
+// allowing other transactions to lock them for share. It requires a
+// transaction and is not supported by SQLite.
+//
+//	tx.ForShare().Find(&user, id)
+func (c *Connection) ForShare() *Query {
+	return Q(c).ForShare()
+}
+
+// ForShare locks the selected rows against concurrent updates, while still
+// allowing other transactions to lock them for share. It requires a
+// transaction and is not supported by SQLite.
+//
+//	q.ForShare().Find(&user, id)
+func (q *Query) ForShare() *Query {
+	q.lock.Strength = LockShare
+	return q
+}
+
+// NoWait makes a locking query fail at once instead of waiting for rows
+// locked by other transactions. It requires ForUpdate or ForShare, and is not
+// supported by MySQL 5.7.
+//
+//	q.ForUpdate().NoWait().Find(&user, id)
+func (q *Query) NoWait() *Query {
+	q.lock.Wait = LockNoWait
+	return q
+}
+
+// SkipLocked makes a locking query skip rows locked by other transactions
+// instead of waiting for them. It requires ForUpdate or ForShare, and is not
+// supported by MySQL 5.7.
+//
+//	q.ForUpdate().SkipLocked().Limit(10).All(&jobs)
+func (q *Query) SkipLocked() *Query {
+	q.lock.Wait = LockSkipLocked
+	return q
+}
+
+// Distinct will only select distinct rows. Given columns, only the first row
+// of each set of rows with the same values of these columns is selected,
+// with DISTINCT ON, which is only supported by the postgres and cockroach
+// dialects. The order of the query should then start with these columns.
+//
+//	c.Distinct().Pluck(&User{}, "name", &names)
+//	c.Distinct("user_id").Order("user_id, created_at desc").All(&books)
+func (c *Connection) Distinct(columns ...string) *Query {
+	return Q(c).Distinct(columns...)
+}
+
+// Distinct will only select distinct rows. Given columns, only the first row
+// of each set of rows with the same values of these columns is selected,
+// with DISTINCT ON, which is only supported by the postgres and cockroach
+// dialects. The order of the query should then start with these columns.
+//
+//	q.Distinct("user_id").Order("user_id, created_at desc").All(&books)
+func (q *Query) Distinct(columns ...string) *Query {
+	q.distinct = true
+	q.distinctOn = append(q.distinctOn, columns...)
+	return q
+}
+
+// Timeout bounds each statement of the query to the given duration. A
+// statement taking longer is canceled, and returns an error matching
+// ErrTimeout. The statement_timeout option of the connection still applies,
+// so it can only be shortened.
+//
+//	c.Timeout(2 * time.Second).Where("name = ?", "Mark").All(&users)
+func (c *Connection) Timeout(d time.Duration) *Query {
+	return Q(c).Timeout(d)
+}
+
+// Timeout bounds each statement of the query to the given duration. A
+// statement taking longer is canceled, and returns an error matching
+// ErrTimeout. The statement_timeout option of the connection still applies,
+// so it can only be shortened.
+//
+//	q.Where("name = ?", "Mark").Timeout(2 * time.Second).All(&users)
+func (q *Query) Timeout(d time.Duration) *Query {
+	q.Connection = q.Connection.withTimeout(d)
+	return q
+}
+
+// checkClauses reports clauses of the query which can not be used with the
+// connection.
+func (q *Query) checkClauses() error {
+	if err := q.checkDistinct(); err != nil {
+		return err
+	}
+	if err := q.checkWith(); err != nil {
+		return err
+	}
+	if err := q.checkSetOperations(); err != nil {
+		return err
+	}
+	return q.checkLock()
+}
+
+// checkWith reports common table expressions on MySQL 5.7, which does not
+// support them.
+func (q *Query) checkWith() error {
+	if len(q.withClauses) == 0 {
+		return nil
+	}
+	if !supports(q.Connection.Dialect, FeatureWith) {
+		return fmt.Errorf("WITH is not supported by this version of the %s server", q.Connection.Dialect.Name())
+	}
+	return nil
+}
+
+// checkDistinct reports DISTINCT ON clauses on dialects without support.
+func (q *Query) checkDistinct() error {
+	if len(q.distinctOn) == 0 || supportsDistinctOn(q.Connection.Dialect) {
+		return nil
+	}
+	return fmt.Errorf("DISTINCT ON is not supported by the %s dialect", q.Connection.Dialect.Name())
+}
+
+func supportsDistinctOn(d Dialect) bool {
+	switch d.Name() {
+	case namePostgreSQL, nameCockroach:
+		return true
+	}
+	return false
+}
+
+// checkLock reports locking clauses which can not be used with the
+// connection of the query.
+func (q *Query) checkLock() error {
+	if q.lock == (RowLock{}) {
+		return nil
+	}
+	if q.lock.Strength == "" {
+		return fmt.Errorf("%s requires ForUpdate or ForShare", q.lock.Wait)
+	}
+	if _, ok := q.Connection.Dialect.(lockable); !ok {
+		return fmt.Errorf("row locking is not supported by the %s dialect", q.Connection.Dialect.Name())
+	}
+	if q.lock.Wait != "" && !supports(q.Connection.Dialect, FeatureLockWait) {
+		return fmt.Errorf("%s is not supported by this version of the %s server", q.lock.Wait, q.Connection.Dialect.Name())
+	}
+	if q.Connection.TX == nil {
+		return fmt.Errorf("FOR %s requires a transaction, the lock would be released at once", q.lock.Strength)
+	}
+	return nil
+}
+
 // Q will create a new "empty" query from the current connection.
 func Q(c *Connection) *Query {
 	return &Query{
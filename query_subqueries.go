@@ -0,0 +1,115 @@
+package pop
+
+import (
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6/logging"
+)
+
+// fromSubqueryClause replaces the table of the model in the FROM clause.
+type fromSubqueryClause struct {
+	clause
+	As string
+}
+
+// From sets the model a query selects from when it is used as a subquery
+// of another query, see With, WhereIn, WhereExists and FromSubquery. It has
+// no effect on the finders, which select from the model they are given.
+//
+//	sub := c.Q().From(&Book{}).Select("user_id").Where("title = ?", "Pop")
+//	c.WhereIn("id", sub).All(&users)
+func (q *Query) From(model interface{}) *Query {
+	q.fromModel = model
+	return q
+}
+
+// With will prepend a common table expression to the query, which can then
+// be referred to by name in the other clauses. It is not supported by MySQL
+// 5.7.
+//
+//	recent := c.Q().From(&Book{}).Where("created_at > ?", since)
+//	c.Q().With("recent_books", recent).Join("recent_books rb", "rb.user_id = users.id").All(&users)
+func (q *Query) With(name string, sub *Query) *Query {
+	if q.RawSQL.Fragment != "" {
+		log(logging.Warn, "Query is setup to use raw SQL")
+		return q
+	}
+	stmt, args, ok := sub.subquery()
+	if !ok {
+		return q
+	}
+	q.withClauses = append(q.withClauses, clause{fmt.Sprintf("%s AS (%s)", name, stmt), args})
+	return q
+}
+
+// WhereIn will append a where clause matching the rows whose column is
+// among the values selected by the subquery.
+//
+//	sub := c.Q().From(&Book{}).Select("user_id").Where("title = ?", "Pop")
+//	c.Q().WhereIn("id", sub).All(&users)
+func (q *Query) WhereIn(column string, sub *Query) *Query {
+	if q.RawSQL.Fragment != "" {
+		log(logging.Warn, "Query is setup to use raw SQL")
+		return q
+	}
+	stmt, args, ok := sub.subquery()
+	if !ok {
+		return q
+	}
+	q.whereClauses = append(q.whereClauses, clause{fmt.Sprintf("%s IN (%s)", column, stmt), args})
+	return q
+}
+
+// WhereExists will append a where clause matching the rows for which the
+// subquery selects at least one row.
+//
+//	sub := c.Q().From(&Book{}).Where("books.user_id = users.id")
+//	c.Q().WhereExists(sub).All(&users)
+func (q *Query) WhereExists(sub *Query) *Query {
+	if q.RawSQL.Fragment != "" {
+		log(logging.Warn, "Query is setup to use raw SQL")
+		return q
+	}
+	stmt, args, ok := sub.subquery()
+	if !ok {
+		return q
+	}
+	q.whereClauses = append(q.whereClauses, clause{fmt.Sprintf("EXISTS (%s)", stmt), args})
+	return q
+}
+
+// FromSubquery will select the rows of the subquery instead of the table of
+// the model. The columns of the model are selected from the subquery under
+// the given alias.
+//
+//	latest := c.RawQuery("SELECT * FROM books WHERE created_at > ?", since)
+//	c.Q().FromSubquery(latest, "books").Where("books.user_id = ?", id).All(&books)
+func (q *Query) FromSubquery(sub *Query, alias string) *Query {
+	if q.RawSQL.Fragment != "" {
+		log(logging.Warn, "Query is setup to use raw SQL")
+		return q
+	}
+	stmt, args, ok := sub.subquery()
+	if !ok {
+		return q
+	}
+	q.fromSubquery = &fromSubqueryClause{clause{fmt.Sprintf("(%s)", stmt), args}, alias}
+	return q
+}
+
+// subquery returns the SQL and arguments of the query to embed in another
+// one. The SQL keeps the `?` bindvars, so its arguments can be merged with
+// those of the outer query before the statement is translated for the
+// dialect.
+func (q *Query) subquery() (string, []interface{}, bool) {
+	if q.RawSQL.Fragment != "" {
+		return q.RawSQL.Fragment, q.RawSQL.Arguments, true
+	}
+	if q.fromModel == nil {
+		log(logging.Warn, "Subquery ignored, it has neither a model set with From nor raw SQL")
+		return "", nil, false
+	}
+	sb := q.toSQLBuilder(NewModel(q.fromModel, q.Connection.Context()))
+	sb.build()
+	return sb.sql, sb.args, true
+}
@@ -0,0 +1,88 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Query_Subqueries_ToSQL(t *testing.T) {
+	r := require.New(t)
+
+	pg, err := NewConnection(&ConnectionDetails{Dialect: "postgres", Database: "pop_test"})
+	r.NoError(err)
+	book := NewModel(&Book{}, pg.Context())
+
+	recent := pg.Q().From(&User{}).Select("id").Where("created_at > ?", "2020-01-01")
+	q, args := pg.Q().
+		With("recent_users", recent).
+		Select("books.id").
+		WhereIn("books.user_id", pg.Q().From(&User{}).Select("id").Where("name = ?", "Mark")).
+		WhereExists(pg.RawQuery("SELECT 1 FROM recent_users WHERE recent_users.id = books.user_id AND 1 = ?", 1)).
+		Where("books.title = ?", "Pop").
+		ToSQL(book)
+	r.Equal("WITH recent_users AS (SELECT id FROM users AS users WHERE created_at > $1) "+
+		"SELECT books.id FROM books AS books "+
+		"WHERE books.user_id IN (SELECT id FROM users AS users WHERE name = $2) "+
+		"AND EXISTS (SELECT 1 FROM recent_users WHERE recent_users.id = books.user_id AND 1 = $3) "+
+		"AND books.title = $4", q)
+	r.Equal([]interface{}{"2020-01-01", "Mark", 1, "Pop"}, args)
+
+	q, args = pg.Q().
+		FromSubquery(pg.Q().From(&Book{}).Where("title = ?", "Pop"), "pop_books").
+		Where("pop_books.isbn = ?", "123").
+		ToSQL(book)
+	r.Contains(q, " pop_books.title, ")
+	r.Contains(q, " FROM (SELECT books.created_at, ")
+	r.Contains(q, " FROM books AS books WHERE title = $1) AS pop_books WHERE pop_books.isbn = $2")
+	r.Equal([]interface{}{"Pop", "123"}, args)
+
+	// subqueries without a model are ignored
+	q, _ = pg.Q().WhereExists(pg.Q().Where("id = ?", 1)).ToSQL(book)
+	r.NotContains(q, "WHERE")
+
+	my, err := NewConnection(&ConnectionDetails{Dialect: "mysql", Database: "pop_test"})
+	r.NoError(err)
+	my.Dialect.(*mysql).version = "5.7.44"
+	r.EqualError(my.Q().With("recent_users", recent).All(&[]Book{}), "WITH is not supported by this version of the mysql server")
+}
+
+func Test_Query_Subqueries(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		mark := User{Name: nulls.NewString("Mark")}
+		r.NoError(tx.Create(&mark))
+		paul := User{Name: nulls.NewString("Paul")}
+		r.NoError(tx.Create(&paul))
+		r.NoError(tx.Create(&Book{Title: "Pop", Isbn: "1", UserID: nulls.NewInt(mark.ID)}))
+
+		users := []User{}
+		sub := tx.Q().From(&Book{}).Select("user_id").Where("title = ?", "Pop")
+		r.NoError(tx.Q().WhereIn("id", sub).Where("name IS NOT NULL").All(&users))
+		r.Len(users, 1)
+		r.Equal(mark.ID, users[0].ID)
+
+		users = []User{}
+		sub = tx.Q().From(&Book{}).Where("books.user_id = users.id")
+		r.NoError(tx.Q().WhereExists(sub).Order("id").All(&users))
+		r.Len(users, 1)
+		r.Equal(mark.ID, users[0].ID)
+
+		if supports(tx.Dialect, FeatureWith) {
+			users = []User{}
+			readers := tx.Q().From(&Book{}).Select("user_id").Where("title = ?", "Pop")
+			r.NoError(tx.Q().With("readers", readers).Where("id NOT IN (SELECT user_id FROM readers)").Where("id IN (?)", mark.ID, paul.ID).All(&users))
+			r.Len(users, 1)
+			r.Equal(paul.ID, users[0].ID)
+		}
+
+		count, err := tx.Q().FromSubquery(tx.Q().From(&User{}).Where("name = ?", "Paul"), "users").Count(&User{})
+		r.NoError(err)
+		r.Equal(1, count)
+	})
+}
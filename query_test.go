@@ -3,8 +3,11 @@ package pop
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gobuffalo/nulls"
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -405,3 +408,156 @@ func Test_RawQuery_Empty(t *testing.T) {
 		})
 	})
 }
+
+func Test_Query_Lock(t *testing.T) {
+	r := require.New(t)
+
+	pg, err := NewConnection(&ConnectionDetails{Dialect: "postgres", Database: "pop_test"})
+	r.NoError(err)
+	user := NewModel(&User{}, pg.Context())
+
+	q, _ := pg.ForUpdate().SkipLocked().Where("id = ?", 1).Limit(1).ToSQL(user)
+	r.True(strings.HasSuffix(q, "WHERE id = $1 LIMIT 1 FOR UPDATE SKIP LOCKED"), q)
+
+	q, _ = pg.ForShare().NoWait().ToSQL(user)
+	r.True(strings.HasSuffix(q, "FROM users AS users FOR SHARE NOWAIT"), q)
+
+	my, err := NewConnection(&ConnectionDetails{Dialect: "mysql", Database: "pop_test"})
+	r.NoError(err)
+	q, _ = my.ForShare().ToSQL(user)
+	r.True(strings.HasSuffix(q, "FOR SHARE"), q)
+
+	mariadb, err := NewConnection(&ConnectionDetails{Dialect: "mariadb", Database: "pop_test"})
+	r.NoError(err)
+	q, _ = mariadb.ForShare().SkipLocked().ToSQL(user)
+	r.True(strings.HasSuffix(q, "LOCK IN SHARE MODE SKIP LOCKED"), q)
+
+	my.Dialect.(*mysql).version = "5.7.44-log"
+	q, _ = my.ForShare().ToSQL(user)
+	r.True(strings.HasSuffix(q, "LOCK IN SHARE MODE"), q)
+	r.EqualError(my.ForUpdate().SkipLocked().First(&User{}), "SKIP LOCKED is not supported by this version of the mysql server")
+
+	// dialects wrapping MySQL get the checks through Supports
+	wrapped := &Connection{Dialect: struct{ *mysql }{my.Dialect.(*mysql)}, TX: &Tx{}}
+	r.EqualError(wrapped.ForUpdate().NoWait().First(&User{}), "NOWAIT is not supported by this version of the mysql server")
+	r.EqualError(wrapped.Q().With("recent", my.Q().From(&User{})).All(&[]User{}), "WITH is not supported by this version of the mysql server")
+
+	r.EqualError(pg.ForUpdate().First(&User{}), "FOR UPDATE requires a transaction, the lock would be released at once")
+	r.EqualError(Q(pg).NoWait().First(&User{}), "NOWAIT requires ForUpdate or ForShare")
+
+	yc := &Connection{Dialect: &ydb{}, TX: &Tx{}}
+	r.EqualError(yc.ForUpdate().All(&[]User{}), "row locking is not supported by the ydb dialect")
+}
+
+func Test_Query_Lock_Transaction(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		user := User{Name: nulls.NewString("Locked")}
+		r.NoError(tx.Create(&user))
+
+		err := tx.ForUpdate().SkipLocked().Find(&User{}, user.ID)
+		if tx.Dialect.Name() == nameSQLite3 {
+			r.EqualError(err, "row locking is not supported by the sqlite3 dialect")
+			return
+		}
+		if supports(tx.Dialect, FeatureLockWait) {
+			r.NoError(err)
+		} else {
+			r.Error(err)
+		}
+
+		r.NoError(tx.ForShare().Find(&User{}, user.ID))
+
+		count, err := tx.ForUpdate().Where("id = ?", user.ID).Count(&User{})
+		r.NoError(err)
+		r.Equal(1, count)
+	})
+}
+
+func Test_Query_Distinct(t *testing.T) {
+	r := require.New(t)
+
+	pg, err := NewConnection(&ConnectionDetails{Dialect: "postgres", Database: "pop_test"})
+	r.NoError(err)
+	user := NewModel(&User{}, pg.Context())
+
+	q, _ := pg.Distinct().Select("name").ToSQL(user)
+	r.Equal("SELECT DISTINCT name FROM users AS users", q)
+
+	q, _ = pg.Distinct("name", "email").Select("id").Order("name, email, id desc").ToSQL(user)
+	r.Equal("SELECT DISTINCT ON (name, email) id FROM users AS users ORDER BY name, email, id desc", q)
+
+	my, err := NewConnection(&ConnectionDetails{Dialect: "mysql", Database: "pop_test"})
+	r.NoError(err)
+	r.EqualError(my.Distinct("name").All(&[]User{}), "DISTINCT ON is not supported by the mysql dialect")
+}
+
+func Test_Query_Timeout(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+		slow := slowStatement(t, tx.Dialect)
+
+		r.NoError(tx.Timeout(time.Minute).Where("name = ?", "Mark").All(&Users{}))
+
+		start := time.Now()
+		err := tx.Timeout(50 * time.Millisecond).RawQuery(slow).Exec()
+		r.ErrorIs(err, ErrTimeout)
+		r.True(time.Since(start) < 5*time.Second)
+	})
+}
+
+func Test_Connection_StatementTimeout(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	if !hasStatementTimeout(PDB.Dialect.Name()) {
+		t.Skip("the statement_timeout option only limits lock waits or SELECT statements")
+	}
+	r := require.New(t)
+	slow := slowStatement(t, PDB.Dialect)
+
+	deets := *PDB.Dialect.Details()
+	deets.Options = map[string]string{}
+	for k, v := range PDB.Dialect.Details().Options {
+		deets.Options[k] = v
+	}
+	deets.Options["statement_timeout"] = "50"
+	if deets.URL != "" {
+		// the options are only used without a URL
+		deets.URL += "&statement_timeout=50"
+	}
+	c, err := NewConnection(&deets)
+	r.NoError(err)
+	r.NoError(c.Open())
+	defer c.Close()
+	r.Contains(c.URL(), "statement_timeout=50")
+
+	r.NoError(c.Where("name = ?", "Mark").All(&Users{}))
+
+	// the server cancels the statements of every connection of the pool
+	start := time.Now()
+	r.ErrorIs(c.translateError(c.RawQuery(slow).Exec()), ErrTimeout)
+	r.ErrorIs(c.translateError(c.Transaction(func(tx *Connection) error {
+		return tx.RawQuery(slow).Exec()
+	})), ErrTimeout)
+	r.True(time.Since(start) < 10*time.Second)
+}
+
+// slowStatement returns a statement running for seconds on the dialect.
+func slowStatement(t *testing.T, d Dialect) string {
+	switch d.Name() {
+	case nameSQLite3:
+		return "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM c WHERE x < 1000000000) SELECT count(*) FROM c"
+	case namePostgreSQL, nameCockroach:
+		return "SELECT pg_sleep(10)"
+	}
+	t.Skip("no slow statement for the dialect")
+	return ""
+}
@@ -0,0 +1,78 @@
+package pop
+
+import (
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6/logging"
+)
+
+// Union will combine the rows of the query with those of the other query,
+// without duplicates. Order, Limit and Paginate apply to the combined rows;
+// the order set before the first set operation and those of the other query
+// are ignored. The other query selects from the same model, unless another one
+// is set with From. The rows are combined from left to right, whatever the
+// precedence of the operators in the dialect. Row locks can't be used.
+//
+//	active := c.Where("status = ?", "active")
+//	invited := c.Where("status = ?", "invited")
+//	active.Union(invited).Order("name").Paginate(1, 20).All(&users)
+func (q *Query) Union(other *Query) *Query {
+	return q.combine("UNION", other)
+}
+
+// UnionAll will combine the rows of the query with those of the other query,
+// keeping duplicates. See Union.
+func (q *Query) UnionAll(other *Query) *Query {
+	return q.combine("UNION ALL", other)
+}
+
+// Intersect will only keep the rows of the query which are also selected by
+// the other query. See Union. It is not supported by MySQL before 8.0.31.
+func (q *Query) Intersect(other *Query) *Query {
+	return q.combine("INTERSECT", other)
+}
+
+// Except will remove the rows selected by the other query from the rows of
+// the query. See Union. It is not supported by MySQL before 8.0.31.
+func (q *Query) Except(other *Query) *Query {
+	return q.combine("EXCEPT", other)
+}
+
+func (q *Query) combine(operator string, other *Query) *Query {
+	if q.RawSQL.Fragment != "" {
+		log(logging.Warn, "Query is setup to use raw SQL")
+		return q
+	}
+	oq := Q(other.Connection)
+	other.Clone(oq)
+	if len(q.setClauses) == 0 {
+		// the order of the first query would be merged with the one of the
+		// combined rows.
+		q.orderClauses = clauses{}
+	}
+	q.setClauses = append(q.setClauses, setClause{operator, oq})
+	return q
+}
+
+// checkSetOperations reports INTERSECT and EXCEPT on MySQL before 8.0.31, and
+// row locks, which can't be used with set operations.
+func (q *Query) checkSetOperations() error {
+	if len(q.setClauses) == 0 {
+		return nil
+	}
+	if q.lock != (RowLock{}) {
+		return fmt.Errorf("row locking can not be used with %s", q.setClauses[0].Operator)
+	}
+	for _, sc := range q.setClauses {
+		if sc.Query.lock != (RowLock{}) {
+			return fmt.Errorf("row locking can not be used with %s", sc.Operator)
+		}
+		if (sc.Operator == "INTERSECT" || sc.Operator == "EXCEPT") && !supports(q.Connection.Dialect, FeatureIntersect) {
+			return fmt.Errorf("%s is not supported by this version of the %s server", sc.Operator, q.Connection.Dialect.Name())
+		}
+		if err := sc.Query.checkSetOperations(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
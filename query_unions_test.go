@@ -0,0 +1,92 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Query_Union_ToSQL(t *testing.T) {
+	r := require.New(t)
+
+	pg, err := NewConnection(&ConnectionDetails{Dialect: "postgres", Database: "pop_test"})
+	r.NoError(err)
+	user := NewModel(&User{}, pg.Context())
+
+	active := pg.Select("id", "name").Where("alive = ?", true).Order("name")
+	invited := pg.Where("email LIKE ?", "%@invited").Limit(3)
+	q, args := active.Union(invited).Except(pg.RawQuery("SELECT id, name FROM banned WHERE since > ?", 1)).Order("name desc").Limit(10).ToSQL(user)
+	r.Equal("SELECT * FROM ("+
+		"SELECT * FROM ("+
+		"SELECT id, name FROM users AS users WHERE alive = $1 "+
+		"UNION SELECT id, name FROM users AS users WHERE email LIKE $2"+
+		") AS users "+
+		"EXCEPT SELECT id, name FROM banned WHERE since > $3"+
+		") AS users ORDER BY name desc LIMIT 10", q)
+	r.Equal([]interface{}{true, "%@invited", 1}, args)
+
+	q, _ = pg.Where("id = ?", 1).UnionAll(pg.Where("id = ?", 2)).UnionAll(pg.Where("id = ?", 3)).Intersect(pg.Where("id = ?", 4)).ToSQL(user)
+	r.Contains(q, "SELECT * FROM (SELECT * FROM (SELECT ")
+	r.Contains(q, " WHERE id = $2 UNION ALL SELECT ")
+	r.Contains(q, " WHERE id = $3) AS users INTERSECT SELECT ")
+	r.Contains(q, " WHERE id = $4) AS users")
+
+	r.EqualError(pg.ForUpdate().Union(pg.Q()).All(&[]User{}), "row locking can not be used with UNION")
+	r.EqualError(pg.Q().Union(pg.ForShare().SkipLocked()).All(&[]User{}), "row locking can not be used with UNION")
+
+	my, err := NewConnection(&ConnectionDetails{Dialect: "mysql", Database: "pop_test"})
+	r.NoError(err)
+	my.Dialect.(*mysql).version = "8.0.30"
+	r.EqualError(my.Q().Union(my.Q()).Except(my.Q()).All(&[]User{}), "EXCEPT is not supported by this version of the mysql server")
+	r.EqualError(my.Q().Intersect(my.Q()).All(&[]User{}), "INTERSECT is not supported by this version of the mysql server")
+}
+
+func Test_Query_Union(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		for _, name := range []string{"Mark", "Paul", "Ringo", "John"} {
+			r.NoError(tx.Create(&User{Name: nulls.NewString(name)}))
+		}
+		beatles := func() *Query { return tx.Where("name IN (?)", "Paul", "Ringo", "John") }
+
+		users := []User{}
+		r.NoError(tx.Where("name = ?", "Mark").Union(tx.Where("name = ?", "Paul")).Union(tx.Where("name = ?", "Paul")).Order("name desc").All(&users))
+		r.Len(users, 2)
+		r.Equal("Paul", users[0].Name.String)
+		r.Equal("Mark", users[1].Name.String)
+
+		count, err := tx.Where("name = ?", "Mark").UnionAll(tx.Where("name = ?", "Mark")).Count(&User{})
+		r.NoError(err)
+		r.Equal(2, count)
+
+		users = []User{}
+		q := tx.Where("name = ?", "Mark").Union(beatles()).Order("name").Paginate(2, 3)
+		r.NoError(q.All(&users))
+		r.Len(users, 1)
+		r.Equal("Ringo", users[0].Name.String)
+		r.Equal(4, q.Paginator.TotalEntriesSize)
+
+		users = []User{}
+		r.NoError(beatles().Intersect(tx.Where("name LIKE ?", "%o%")).Order("name").All(&users))
+		r.Len(users, 2)
+		r.Equal("John", users[0].Name.String)
+
+		users = []User{}
+		r.NoError(beatles().Except(tx.Where("name LIKE ?", "%o%")).All(&users))
+		r.Len(users, 1)
+		r.Equal("Paul", users[0].Name.String)
+
+		// evaluated from left to right, although INTERSECT binds tighter
+		// in PostgreSQL and MySQL.
+		users = []User{}
+		r.NoError(tx.Where("name = ?", "Mark").Union(beatles()).Intersect(tx.Where("name LIKE ?", "%o%")).Order("name").All(&users))
+		r.Len(users, 2)
+		r.Equal("John", users[0].Name.String)
+		r.Equal("Ringo", users[1].Name.String)
+	})
+}
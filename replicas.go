@@ -0,0 +1,125 @@
+package pop
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+const (
+	// ReplicaRoundRobin sends the reads to the replicas in turn.
+	ReplicaRoundRobin = "round_robin"
+	// ReplicaLeastBusy sends the reads to the replica with the fewest
+	// connections in use. Opening the connection fails if the connections
+	// in use of a replica can't be read.
+	ReplicaLeastBusy = "least_busy"
+)
+
+// replicaSet holds the read replicas of a connection.
+type replicaSet struct {
+	dbs      []*dB
+	stores   []Store
+	strategy string
+	next     uint64
+}
+
+// openReplicas opens a database connection for every replica URL of the
+// given connection details, using the same dialect and pool settings.
+func openReplicas(details *ConnectionDetails) (*replicaSet, error) {
+	switch details.ReplicaStrategy {
+	case "", ReplicaRoundRobin, ReplicaLeastBusy:
+	default:
+		return nil, fmt.Errorf("unknown replica strategy '%s'", details.ReplicaStrategy)
+	}
+
+	rs := &replicaSet{strategy: details.ReplicaStrategy}
+	for _, u := range details.Replicas {
+		rd := &ConnectionDetails{
+			Dialect:                   details.Dialect,
+			Driver:                    details.Driver,
+			URL:                       u,
+			Pool:                      details.Pool,
+			IdlePool:                  details.IdlePool,
+			ConnMaxLifetime:           details.ConnMaxLifetime,
+			ConnMaxIdleTime:           details.ConnMaxIdleTime,
+			Unsafe:                    details.Unsafe,
+			UseInstrumentedDriver:     details.UseInstrumentedDriver,
+			InstrumentedDriverOptions: details.InstrumentedDriverOptions,
+			Options:                   map[string]string{},
+		}
+		for k, v := range details.Options {
+			rd.Options[k] = v
+		}
+
+		rc, err := NewConnection(rd)
+		if err != nil {
+			rs.Close()
+			return nil, fmt.Errorf("invalid replica %s: %w", u, err)
+		}
+		if err := rc.Open(); err != nil {
+			rs.Close()
+			return nil, fmt.Errorf("couldn't open replica %s: %w", u, err)
+		}
+		rs.stores = append(rs.stores, rc.Store)
+		if rs.strategy != ReplicaLeastBusy {
+			continue
+		}
+		db, ok := storeDB(rc.Store)
+		if !ok {
+			rs.Close()
+			return nil, fmt.Errorf("the %s strategy can't read the connections in use of replica %s", ReplicaLeastBusy, u)
+		}
+		rs.dbs = append(rs.dbs, db)
+	}
+	return rs, nil
+}
+
+// pick returns the store of the replica the next read should be sent to.
+func (rs *replicaSet) pick() Store {
+	n := atomic.AddUint64(&rs.next, 1) - 1
+	i := int(n % uint64(len(rs.stores)))
+
+	if rs.strategy == ReplicaLeastBusy {
+		// start at the round-robin replica, so idle replicas share the load.
+		best, inUse := i, -1
+		for j := 0; j < len(rs.dbs); j++ {
+			k := (i + j) % len(rs.dbs)
+			if u := rs.dbs[k].Stats().InUse; inUse < 0 || u < inUse {
+				best, inUse = k, u
+			}
+		}
+		i = best
+	}
+	return rs.stores[i]
+}
+
+// Close closes the database connections of all replicas.
+func (rs *replicaSet) Close() error {
+	var err error
+	for _, s := range rs.stores {
+		if cerr := s.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// UsePrimary returns a copy of the connection which sends all queries,
+// including reads, to the primary database. Use it when a request must read
+// its own writes, which replicas may not have received yet.
+//
+//	c.Create(&user)
+//	c.UsePrimary().Find(&user, user.ID)
+func (c *Connection) UsePrimary() *Connection {
+	cn := c.copy()
+	cn.usePrimary = true
+	return cn
+}
+
+// readStore returns the store read-only queries are sent to: a replica, unless
+// the connection has none, is in a transaction or uses the primary.
+func (c *Connection) readStore() Store {
+	if c.replicas == nil || len(c.replicas.stores) == 0 || c.TX != nil || c.usePrimary {
+		return c.Store
+	}
+	return contextStore{Store: c.interceptors.wrap(c.replicas.pick(), c.ID, 0), ctx: c.Context(), timeout: c.timeout}
+}
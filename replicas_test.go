@@ -0,0 +1,50 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReplicaSet_Pick(t *testing.T) {
+	r := require.New(t)
+
+	a, b := &dB{}, &dB{}
+	rs := &replicaSet{stores: []Store{a, b}}
+	r.Same(a, rs.pick())
+	r.Same(b, rs.pick())
+	r.Same(a, rs.pick())
+}
+
+func Test_Connection_ReadStore(t *testing.T) {
+	r := require.New(t)
+
+	primary, replica := &dB{}, &dB{}
+	c := &Connection{Store: primary, replicas: &replicaSet{stores: []Store{replica}}}
+
+	s, ok := c.readStore().(contextStore)
+	r.True(ok)
+	r.Same(replica, s.Store)
+
+	r.Same(primary, c.UsePrimary().readStore())
+	r.False(c.usePrimary)
+	r.Same(primary, c.UsePrimary().WithContext(c.Context()).readStore().(contextStore).Store)
+
+	c.TX = &Tx{}
+	r.Same(primary, c.readStore())
+
+	r.Same(primary, (&Connection{Store: primary}).readStore())
+}
+
+func Test_Connection_Open_UnknownReplicaStrategy(t *testing.T) {
+	r := require.New(t)
+
+	c, err := NewConnection(&ConnectionDetails{
+		URL:             "postgres://localhost/pop_test",
+		Replicas:        []string{"postgres://replica/pop_test"},
+		ReplicaStrategy: "random",
+	})
+	r.NoError(err)
+	r.Error(c.Open())
+	r.Nil(c.Store)
+}
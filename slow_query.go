@@ -0,0 +1,161 @@
+package pop
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SlowQuery is a statement which took longer than the slow query threshold of
+// its connection.
+type SlowQuery struct {
+	// SQL is the normalised statement: its literals and bindvars are replaced
+	// by "?", lists of them are collapsed, and its whitespaces are collapsed,
+	// so the occurrences of a statement can be grouped.
+	SQL string
+	// Args is the number of arguments of the statement.
+	Args int
+	// Caller is the location of the code outside of pop which ran the
+	// statement, as "file:line".
+	Caller string
+	// Duration of the statement.
+	Duration time.Duration
+	// ConnectionID is the ID of the connection running the statement.
+	ConnectionID string
+	// TxID is the ID of the transaction running the statement, 0 outside of
+	// one.
+	TxID int
+}
+
+// SetSlowQueryLogger overrides the default slow query logger, which logs the
+// slow queries with the default logger, regardless of Debug.
+func SetSlowQueryLogger(logger func(q SlowQuery)) {
+	slowQueryLog = logger
+}
+
+var slowQueryLog = func(q SlowQuery) {
+	defaultStdLogger.Printf("slow query (%s, %d args) at %s: %s (conn=%v, tx=%v)", q.Duration, q.Args, q.Caller, q.SQL, q.ConnectionID, q.TxID)
+}
+
+// slowQueryRand returns the random numbers sampling the slow queries.
+var slowQueryRand = rand.Float64
+
+// slowQueryInterceptor times the statements and logs those which took longer
+// than the threshold, in the proportion of the sample rate.
+func slowQueryInterceptor(threshold time.Duration, sampleRate float64) Interceptor {
+	return func(stmt *Statement, next func(*Statement) error) error {
+		start := time.Now()
+		err := next(stmt)
+		elapsed := time.Since(start)
+		if elapsed < threshold || (sampleRate > 0 && sampleRate < 1 && slowQueryRand() >= sampleRate) {
+			return err
+		}
+
+		slowQueryLog(SlowQuery{
+			SQL:          normalizeSQL(stmt.SQL),
+			Args:         len(stmt.Args),
+			Caller:       externalCaller(),
+			Duration:     elapsed,
+			ConnectionID: stmt.ConnectionID,
+			TxID:         stmt.TxID,
+		})
+		return err
+	}
+}
+
+const popPackage = "github.com/gobuffalo/pop/v6"
+
+// externalCaller returns the location of the first caller outside of pop, or
+// of a pop test.
+func externalCaller() string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !isPopFrame(f) {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// isPopFrame tells if the frame is in pop or in one of the libraries pop runs
+// the statements with.
+func isPopFrame(f runtime.Frame) bool {
+	if strings.HasSuffix(f.File, "_test.go") {
+		return false
+	}
+	for _, p := range []string{popPackage + ".", popPackage + "/", "github.com/jmoiron/sqlx.", "database/sql.", "runtime."} {
+		if strings.HasPrefix(f.Function, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSQL replaces the literals and bindvars of a statement with "?",
+// collapses the lists of them and the whitespaces.
+func normalizeSQL(query string) string {
+	sb := &strings.Builder{}
+	rs := []rune(query)
+	space := false
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			space = sb.Len() > 0
+			continue
+		case r == '\'':
+			// string literal, with '' escaping a quote.
+			for i++; i < len(rs); i++ {
+				if rs[i] == '\'' {
+					if i+1 < len(rs) && rs[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			r = '?'
+		case (r == '$' || r == ':') && i+1 < len(rs) && (unicode.IsDigit(rs[i+1]) || (r == ':' && isIdentRune(rs[i+1]) && (i == 0 || rs[i-1] != ':'))):
+			// bindvar: $1 or :name, but not a :: cast.
+			for i+1 < len(rs) && isIdentRune(rs[i+1]) {
+				i++
+			}
+			r = '?'
+		case unicode.IsDigit(r) && (i == 0 || !isIdentRune(rs[i-1])):
+			for i+1 < len(rs) && (unicode.IsDigit(rs[i+1]) || rs[i+1] == '.') {
+				i++
+			}
+			r = '?'
+		}
+		if space {
+			sb.WriteRune(' ')
+			space = false
+		}
+		sb.WriteRune(r)
+	}
+	return collapseLists(sb.String())
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// collapseLists collapses the lists of "?", such as "(?, ?, ?)", into "(?)".
+func collapseLists(query string) string {
+	for {
+		s := strings.ReplaceAll(query, "?, ?", "?")
+		s = strings.ReplaceAll(s, "?,?", "?")
+		if s == query {
+			return s
+		}
+		query = s
+	}
+}
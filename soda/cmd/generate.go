@@ -16,5 +16,6 @@ func init() {
 	generateCmd.AddCommand(generate.FizzCmd)
 	generateCmd.AddCommand(generate.SQLCmd)
 	generateCmd.AddCommand(generate.ModelCmd)
+	generateCmd.AddCommand(generate.AuditCmd)
 	RootCmd.AddCommand(generateCmd)
 }
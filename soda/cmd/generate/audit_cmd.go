@@ -0,0 +1,66 @@
+package generate
+
+import (
+	"context"
+
+	"github.com/gobuffalo/fizz"
+	"github.com/gobuffalo/genny/v2"
+	"github.com/gobuffalo/logger"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/pop/v6/genny/fizz/caudit"
+	"github.com/spf13/cobra"
+)
+
+var auditCmdConfig struct {
+	MigrationType string
+}
+
+func init() {
+	AuditCmd.Flags().StringVarP(&auditCmdConfig.MigrationType, "migration-type", "", "fizz", "sets the type of migration files for the audit table (sql or fizz)")
+}
+
+// AuditCmd generates the migration of an audit table
+var AuditCmd = &cobra.Command{
+	Use:   "audit [table]",
+	Short: "Generates the migration of the <table>_history audit table of a model, or of the shared audit_log table without a table.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		run := genny.WetRunner(context.Background())
+
+		// Ensure the generator is as verbose as the old one.
+		lg := logger.New(logger.DebugLevel)
+		run.Logger = lg
+
+		p := cmd.Flag("path")
+		path := ""
+		if p != nil {
+			path = p.Value.String()
+		}
+		var translator fizz.Translator
+		if auditCmdConfig.MigrationType == "sql" {
+			e := cmd.Flag("env")
+			db, err := pop.Connect(e.Value.String())
+			if err != nil {
+				return err
+			}
+			translator = db.Dialect.FizzTranslator()
+		}
+
+		g, err := caudit.New(&caudit.Options{
+			TableName:  name,
+			Path:       path,
+			Type:       auditCmdConfig.MigrationType,
+			Translator: translator,
+		})
+		if err != nil {
+			return err
+		}
+		run.With(g)
+
+		return run.Run()
+	},
+}
@@ -0,0 +1,35 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AuditCmd(t *testing.T) {
+	r := require.New(t)
+	c := AuditCmd
+
+	tdir := t.TempDir()
+
+	pwd, err := os.Getwd()
+	r.NoError(err)
+	os.Chdir(tdir)
+	defer os.Chdir(pwd)
+
+	c.SetArgs([]string{"users"})
+	r.NoError(c.Execute())
+
+	c.SetArgs([]string{})
+	r.NoError(c.Execute())
+
+	up, err := filepath.Glob(filepath.Join(tdir, "migrations", "*_create_users_history.up.fizz"))
+	r.NoError(err)
+	r.Len(up, 1)
+
+	up, err = filepath.Glob(filepath.Join(tdir, "migrations", "*_create_audit_log.up.fizz"))
+	r.NoError(err)
+	r.Len(up, 1)
+}
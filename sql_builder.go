@@ -73,62 +73,128 @@ var inRegex = regexp.MustCompile(`(?i)in\s*\(\s*\?\s*\)`)
 
 func (sq *sqlBuilder) compile() {
 	if sq.sql == "" {
-		if sq.Query.RawSQL.Fragment != "" {
-			if sq.Query.Paginator != nil && !hasLimitOrOffset(sq.Query.RawSQL.Fragment) {
-				sq.sql = sq.buildPaginationClauses(sq.Query.RawSQL.Fragment)
-			} else {
-				if sq.Query.Paginator != nil {
-					log(logging.Warn, "Query already contains pagination")
-				}
-				sq.sql = sq.Query.RawSQL.Fragment
-			}
-			sq.args = sq.Query.RawSQL.Arguments
+		sq.build()
+		sq.sql = sq.Query.Connection.Dialect.TranslateSQL(sq.sql)
+	}
+}
+
+// build builds the SQL and arguments of the query, with `?` bindvars.
+func (sq *sqlBuilder) build() {
+	if sq.Query.RawSQL.Fragment != "" {
+		if sq.Query.Paginator != nil && !hasLimitOrOffset(sq.Query.RawSQL.Fragment) {
+			sq.sql = sq.buildPaginationClauses(sq.Query.RawSQL.Fragment)
 		} else {
-			if sq.Model == nil {
-				sq.err = fmt.Errorf("sqlBuilder.compile() called but no RawSQL and Model specified")
-				return
-			}
-			switch sq.Query.Operation {
-			case Select:
-				sq.sql = sq.buildSelectSQL()
-			case Delete:
-				sq.sql = sq.buildDeleteSQL()
-			default:
-				panic("unexpected query operation " + sq.Query.Operation)
+			if sq.Query.Paginator != nil {
+				log(logging.Warn, "Query already contains pagination")
 			}
+			sq.sql = sq.Query.RawSQL.Fragment
 		}
+		sq.args = sq.Query.RawSQL.Arguments
+	} else {
+		if sq.Model == nil {
+			sq.err = fmt.Errorf("sqlBuilder.compile() called but no RawSQL and Model specified")
+			return
+		}
+		switch sq.Query.Operation {
+		case Select:
+			sq.sql = sq.buildSelectSQL()
+		case Delete:
+			sq.sql = sq.buildDeleteSQL()
+		default:
+			panic("unexpected query operation " + sq.Query.Operation)
+		}
+	}
 
-		if inRegex.MatchString(sq.sql) {
-			s, args, err := sqlx.In(sq.sql, sq.Args()...)
-			if err == nil {
-				sq.sql = s
-				sq.args = args
-			}
+	if inRegex.MatchString(sq.sql) {
+		s, args, err := sqlx.In(sq.sql, sq.args...)
+		if err == nil {
+			sq.sql = s
+			sq.args = args
 		}
-		sq.sql = sq.Query.Connection.Dialect.TranslateSQL(sq.sql)
 	}
 }
 
 func (sq *sqlBuilder) buildSelectSQL() string {
+	if fs := sq.Query.fromSubquery; fs != nil {
+		// the columns are selected from the subquery under its alias.
+		m := *sq.Model
+		m.As = fs.As
+		sq.Model = &m
+	}
+
+	if len(sq.Query.setClauses) > 0 {
+		return sq.buildCompoundSelectSQL()
+	}
+
 	cols := sq.buildColumns()
 
+	sql := sq.buildWithClauses()
+
 	fc := sq.buildfromClauses()
 
-	sql := fmt.Sprintf("SELECT %s FROM %s", cols.Readable().SelectString(), fc)
+	sql += fmt.Sprintf("SELECT %s%s FROM %s", sq.buildDistinctClause(), cols.Readable().SelectString(), fc)
 
 	sql = sq.buildJoinClauses(sql)
 	sql = sq.buildWhereClauses(sql)
 	sql = sq.buildGroupClauses(sql)
 	sql = sq.buildOrderClauses(sql)
 	sql = sq.buildPaginationClauses(sql)
+	sql = sq.buildLockClauses(sql)
+
+	return sql
+}
+
+// buildCompoundSelectSQL combines the query with the queries of its set
+// operations, and selects the combined rows under the alias of the model, so
+// the order and pagination of the query apply to all of them. The rows
+// combined so far are selected from a subquery before another operator, since
+// the precedence of INTERSECT differs between the databases.
+func (sq *sqlBuilder) buildCompoundSelectSQL() string {
+	first := sq.Query
+	first.setClauses = nil
+	sql := sq.buildMemberSQL(first, sq.Model)
+	for i, sc := range sq.Query.setClauses {
+		m := sq.Model
+		if sc.Query.fromModel != nil {
+			m = NewModel(sc.Query.fromModel, sq.Model.ctx)
+		}
+		if i > 0 && sc.Operator != sq.Query.setClauses[i-1].Operator {
+			sql = fmt.Sprintf("SELECT * FROM (%s) AS %s", sql, sq.Model.Alias())
+		}
+		sql = fmt.Sprintf("%s %s %s", sql, sc.Operator, sq.buildMemberSQL(*sc.Query, m))
+	}
+
+	sql = fmt.Sprintf("SELECT * FROM (%s) AS %s", sql, sq.Model.Alias())
+	sql = sq.buildOrderClauses(sql)
+	sql = sq.buildPaginationClauses(sql)
 
 	return sql
 }
 
+// buildMemberSQL builds the SQL of a query combined by a set operation,
+// without its order and pagination.
+func (sq *sqlBuilder) buildMemberSQL(q Query, m *Model) string {
+	q.orderClauses = clauses{}
+	q.limitResults = 0
+	q.Paginator = nil
+	q.lock = RowLock{}
+
+	addColumns := sq.AddColumns
+	if len(q.addColumns) > 0 {
+		addColumns = q.addColumns
+	}
+	mb := newSQLBuilder(q, m, addColumns...)
+	mb.build()
+	sq.args = append(sq.args, mb.args...)
+	return mb.sql
+}
+
 func (sq *sqlBuilder) buildDeleteSQL() string {
+	sql := sq.buildWithClauses()
+
 	fc := sq.buildfromClauses()
 
-	sql := fmt.Sprintf("DELETE FROM %s", fc)
+	sql += fmt.Sprintf("DELETE FROM %s", fc)
 
 	sql = sq.buildWhereClauses(sql)
 
@@ -155,9 +221,13 @@ func (sq *sqlBuilder) buildfromClauses() fromClauses {
 	}
 
 	fc := sq.Query.fromClauses
-	for _, m := range models {
+	for i, m := range models {
 		tableName := m.TableName()
 		asName := m.Alias()
+		if fs := sq.Query.fromSubquery; i == 0 && fs != nil {
+			tableName = fs.Fragment
+			sq.args = append(sq.args, fs.Arguments...)
+		}
 		fc = append(fc, fromClause{
 			From: tableName,
 			As:   asName,
@@ -174,6 +244,18 @@ func (sq *sqlBuilder) buildWhereClauses(sql string) string {
 		sq.Query.Where(fmt.Sprintf("%s.id = %s.%s", sq.Model.TableName(), mc.Through.TableName(), sq.Model.associationName()))
 	}
 
+	if col := sq.Model.softDeleteColumn(); col != "" && !sq.Query.unscoped {
+		// DELETE statements can not use the table alias on every dialect.
+		if sq.Query.Operation != Delete {
+			col = fmt.Sprintf("%s.%s", sq.Model.Alias(), col)
+		}
+		if sq.Query.onlyDeleted {
+			sq.Query.Where(fmt.Sprintf("%s IS NOT NULL", col))
+		} else {
+			sq.Query.Where(fmt.Sprintf("%s IS NULL", col))
+		}
+	}
+
 	wc := sq.Query.whereClauses
 	if len(wc) > 0 {
 		sql = fmt.Sprintf("%s WHERE %s", sql, wc.Join(" AND "))
@@ -182,6 +264,25 @@ func (sq *sqlBuilder) buildWhereClauses(sql string) string {
 	return sql
 }
 
+func (sq *sqlBuilder) buildDistinctClause() string {
+	if !sq.Query.distinct {
+		return ""
+	}
+	if len(sq.Query.distinctOn) > 0 && supportsDistinctOn(sq.Query.Connection.Dialect) {
+		return fmt.Sprintf("DISTINCT ON (%s) ", strings.Join(sq.Query.distinctOn, ", "))
+	}
+	return "DISTINCT "
+}
+
+func (sq *sqlBuilder) buildWithClauses() string {
+	wc := sq.Query.withClauses
+	if len(wc) == 0 {
+		return ""
+	}
+	sq.args = append(sq.args, wc.Args()...)
+	return fmt.Sprintf("WITH %s ", wc.Join(", "))
+}
+
 func (sq *sqlBuilder) buildJoinClauses(sql string) string {
 	oc := sq.Query.joinClauses
 	if len(oc) > 0 {
@@ -239,6 +340,16 @@ func (sq *sqlBuilder) buildPaginationClauses(sql string) string {
 	return sql
 }
 
+func (sq *sqlBuilder) buildLockClauses(sql string) string {
+	if sq.Query.lock.Strength == "" {
+		return sql
+	}
+	if d, ok := sq.Query.Connection.Dialect.(lockable); ok {
+		sql = fmt.Sprintf("%s %s", sql, d.LockClause(sq.Query.lock))
+	}
+	return sql
+}
+
 // columnCache is used to prevent columns rebuilding.
 var columnCache = map[string]columns.Columns{}
 var columnCacheMutex = sync.RWMutex{}
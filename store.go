@@ -3,17 +3,19 @@ package pop
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
 // Store is an interface that must be implemented in order for Pop
 // to be able to use the value as a way of talking to a datastore.
-type store interface {
+type Store interface {
 	Select(interface{}, string, ...interface{}) error
 	Get(interface{}, string, ...interface{}) error
 	NamedExec(string, interface{}) (sql.Result, error)
 	NamedQuery(query string, arg interface{}) (*sqlx.Rows, error)
+	Queryx(query string, args ...interface{}) (*sqlx.Rows, error)
 	Exec(string, ...interface{}) (sql.Result, error)
 	PrepareNamed(string) (*sqlx.NamedStmt, error)
 	Transaction() (*Tx, error)
@@ -26,6 +28,7 @@ type store interface {
 	GetContext(context.Context, interface{}, string, ...interface{}) error
 	NamedExecContext(context.Context, string, interface{}) (sql.Result, error)
 	NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error)
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
 	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
 	PrepareNamedContext(context.Context, string) (*sqlx.NamedStmt, error)
 	TransactionContext(context.Context) (*Tx, error)
@@ -33,28 +36,95 @@ type store interface {
 }
 
 // ContextStore wraps a store with a Context, so passes it with the functions that don't take it.
+// A timeout, if set, bounds each of its statements.
 type contextStore struct {
-	store
-	ctx context.Context
+	Store
+	ctx     context.Context
+	timeout time.Duration
 }
 
 func (s contextStore) Transaction() (*Tx, error) {
-	return s.store.TransactionContext(s.ctx)
+	return s.Store.TransactionContext(s.ctx)
 }
 func (s contextStore) Select(dest interface{}, query string, args ...interface{}) error {
-	return s.store.SelectContext(s.ctx, dest, query, args...)
+	return s.SelectContext(s.ctx, dest, query, args...)
 }
 func (s contextStore) Get(dest interface{}, query string, args ...interface{}) error {
-	return s.store.GetContext(s.ctx, dest, query, args...)
+	return s.GetContext(s.ctx, dest, query, args...)
 }
 func (s contextStore) NamedExec(query string, arg interface{}) (sql.Result, error) {
-	return s.store.NamedExecContext(s.ctx, query, arg)
+	return s.NamedExecContext(s.ctx, query, arg)
+}
+func (s contextStore) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	return s.NamedQueryContext(s.ctx, query, arg)
+}
+func (s contextStore) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
+	return s.QueryxContext(s.ctx, query, args...)
 }
 func (s contextStore) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return s.store.ExecContext(s.ctx, query, args...)
+	return s.ExecContext(s.ctx, query, args...)
 }
 func (s contextStore) PrepareNamed(query string) (*sqlx.NamedStmt, error) {
-	return s.store.PrepareNamedContext(s.ctx, query)
+	return s.Store.PrepareNamedContext(s.ctx, query)
+}
+
+func (s contextStore) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return timeoutError(ctx, s.Store.SelectContext(ctx, dest, query, args...))
+}
+func (s contextStore) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return timeoutError(ctx, s.Store.GetContext(ctx, dest, query, args...))
+}
+func (s contextStore) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	res, err := s.Store.NamedExecContext(ctx, query, arg)
+	return res, timeoutError(ctx, err)
+}
+func (s contextStore) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	res, err := s.Store.ExecContext(ctx, query, args...)
+	return res, timeoutError(ctx, err)
+}
+
+// The rows are read after the statements returning them, so their context is
+// released at the deadline.
+func (s contextStore) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	rows, err := s.Store.NamedQueryContext(ctx, query, arg)
+	if err != nil {
+		cancel()
+		return rows, timeoutError(ctx, err)
+	}
+	if s.timeout > 0 {
+		time.AfterFunc(s.timeout, cancel)
+	}
+	return rows, nil
+}
+func (s contextStore) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	rows, err := s.Store.QueryxContext(ctx, query, args...)
+	if err != nil {
+		cancel()
+		return rows, timeoutError(ctx, err)
+	}
+	if s.timeout > 0 {
+		time.AfterFunc(s.timeout, cancel)
+	}
+	return rows, nil
+}
+
+// withTimeout returns the context of a statement, with the deadline of the
+// timeout of the store if any.
+func (s contextStore) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.timeout)
 }
 
 func (s contextStore) Context() context.Context {
@@ -0,0 +1,220 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/gobuffalo/pop/v6"
+
+// TelemetryOptions configure the OpenTelemetry instrumentation of a
+// connection.
+type TelemetryOptions struct {
+	// TracerProvider creates the spans of the statements. Defaults to the
+	// global tracer provider.
+	TracerProvider trace.TracerProvider
+	// MeterProvider creates the latency and connection pool metrics.
+	// Defaults to the global meter provider.
+	MeterProvider metric.MeterProvider
+	// RedactStatement rewrites the SQL recorded as the db.statement of the
+	// spans, such as to remove literals. Arguments are never recorded. An
+	// empty result omits the attribute.
+	RedactStatement func(sql string) string
+}
+
+// UseTelemetry instruments the connection with OpenTelemetry. Every
+// statement gets a client span carrying the db.system, db.statement,
+// db.operation and db.sql.table attributes and the ID of the connection, and
+// its duration is recorded in the db.client.operation.duration histogram.
+// The db.client.connections.* metrics report the state of the connection
+// pool until the connection is closed.
+//
+//	c.UseTelemetry(pop.TelemetryOptions{TracerProvider: tp, MeterProvider: mp})
+func (c *Connection) UseTelemetry(opts TelemetryOptions) error {
+	tp := opts.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	mp := opts.MeterProvider
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	tracer := tp.Tracer(instrumentationName)
+	meter := mp.Meter(instrumentationName)
+
+	duration, err := meter.Float64Histogram("db.client.operation.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of the database statements."))
+	if err != nil {
+		return err
+	}
+	reg, err := c.observePool(meter)
+	if err != nil {
+		return err
+	}
+	if c.poolMetrics != nil {
+		if err := c.poolMetrics.Unregister(); err != nil {
+			return err
+		}
+	}
+	c.poolMetrics = reg
+
+	system := attribute.String("db.system", dbSystem(c.Dialect))
+	c.Use(func(stmt *Statement, next func(*Statement) error) error {
+		operation := statementOperation(stmt.SQL)
+		attrs := []attribute.KeyValue{system, attribute.String("db.operation", operation)}
+		name := operation
+		if table := statementTable(stmt.Model); table != "" {
+			attrs = append(attrs, attribute.String("db.sql.table", table))
+			name = operation + " " + table
+		}
+
+		spanAttrs := append([]attribute.KeyValue{attribute.String("pop.connection.id", stmt.ConnectionID)}, attrs...)
+		sqlText := stmt.SQL
+		if opts.RedactStatement != nil {
+			sqlText = opts.RedactStatement(sqlText)
+		}
+		if sqlText != "" {
+			spanAttrs = append(spanAttrs, attribute.String("db.statement", sqlText))
+		}
+
+		ctx, span := tracer.Start(stmt.Context, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(spanAttrs...))
+		defer span.End()
+		stmt.Context = ctx
+
+		start := time.Now()
+		err := next(stmt)
+		duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return err
+	})
+	return nil
+}
+
+// observePool reports the statistics of the connection pool, until the
+// returned registration is unregistered.
+func (c *Connection) observePool(meter metric.Meter) (metric.Registration, error) {
+	open, err := meter.Int64ObservableGauge("db.client.connections.open",
+		metric.WithDescription("Number of established connections."))
+	if err != nil {
+		return nil, err
+	}
+	inUse, err := meter.Int64ObservableGauge("db.client.connections.in_use",
+		metric.WithDescription("Number of connections in use."))
+	if err != nil {
+		return nil, err
+	}
+	idle, err := meter.Int64ObservableGauge("db.client.connections.idle",
+		metric.WithDescription("Number of idle connections."))
+	if err != nil {
+		return nil, err
+	}
+	maxOpen, err := meter.Int64ObservableGauge("db.client.connections.max",
+		metric.WithDescription("Maximum number of open connections, 0 for unlimited."))
+	if err != nil {
+		return nil, err
+	}
+	waits, err := meter.Int64ObservableCounter("db.client.connections.wait_count",
+		metric.WithDescription("Number of connections waited for."))
+	if err != nil {
+		return nil, err
+	}
+	waitTime, err := meter.Float64ObservableCounter("db.client.connections.wait_time",
+		metric.WithUnit("s"),
+		metric.WithDescription("Time spent waiting for connections."))
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := metric.WithAttributes(
+		attribute.String("db.system", dbSystem(c.Dialect)),
+		attribute.String("pop.connection.id", c.ID))
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats, ok := storeStats(c.Store)
+		if !ok {
+			return nil
+		}
+		o.ObserveInt64(open, int64(stats.OpenConnections), attrs)
+		o.ObserveInt64(inUse, int64(stats.InUse), attrs)
+		o.ObserveInt64(idle, int64(stats.Idle), attrs)
+		o.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections), attrs)
+		o.ObserveInt64(waits, stats.WaitCount, attrs)
+		o.ObserveFloat64(waitTime, stats.WaitDuration.Seconds(), attrs)
+		return nil
+	}, open, inUse, idle, maxOpen, waits, waitTime)
+}
+
+// storeStats returns the statistics of the database of the store, unless
+// the store is a transaction.
+func storeStats(s Store) (sql.DBStats, bool) {
+	db, ok := storeDB(s)
+	if !ok {
+		return sql.DBStats{}, false
+	}
+	return db.Stats(), true
+}
+
+// dbSystem returns the db.system name of the database of the dialect.
+func dbSystem(d Dialect) string {
+	if d == nil {
+		return "other_sql"
+	}
+	switch CanonicalDialect(d.Details().Dialect) {
+	case namePostgreSQL:
+		return "postgresql"
+	case nameCockroach:
+		return "cockroachdb"
+	case nameMariaDB:
+		return "mariadb"
+	case nameMySQL:
+		return "mysql"
+	case nameSQLite3:
+		return "sqlite"
+	}
+	return d.Name()
+}
+
+// statementOperation returns the first keyword of the statement, such as
+// SELECT or INSERT.
+func statementOperation(sqlText string) string {
+	fields := strings.FieldsFunc(sqlText, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// statementTable returns the table of the model of a statement, if it is a
+// model and not a scalar or an internal destination.
+func statementTable(model interface{}) string {
+	t := reflect.TypeOf(model)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || t.Name() == "" || !unicode.IsUpper(rune(t.Name()[0])) {
+		return ""
+	}
+	if t.Implements(scannerType) || reflect.PtrTo(t).Implements(scannerType) {
+		return ""
+	}
+	return NewModel(model, context.Background()).TableName()
+}
@@ -0,0 +1,162 @@
+package pop
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_StatementTable(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("users", statementTable(&User{}))
+	r.Equal("users", statementTable(&[]User{}))
+	r.Equal("", statementTable(&rowCount{}))
+	r.Equal("", statementTable(&nulls.String{}))
+	r.Equal("", statementTable(&[]string{}))
+	r.Equal("", statementTable(nil))
+
+	r.Equal("SELECT", statementOperation("SELECT * FROM users"))
+	r.Equal("WITH", statementOperation("  with a AS (SELECT 1) SELECT * FROM a"))
+}
+
+func Test_Connection_UseTelemetry(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	c := PDB.copy()
+	r.NoError(c.UseTelemetry(TelemetryOptions{
+		TracerProvider: tp,
+		MeterProvider:  mp,
+		RedactStatement: func(sql string) string {
+			return strings.Replace(sql, "'secret'", "?", 1)
+		},
+	}))
+
+	errRollback := errors.New("rollback")
+	var txID string
+	err := c.Transaction(func(tx *Connection) error {
+		txID = tx.ID
+		user := User{Name: nulls.NewString("Mark")}
+		r.NoError(tx.Create(&user))
+		r.NoError(tx.Where("name != 'secret'").Find(&User{}, user.ID))
+		r.Error(tx.Find(&User{}, -1))
+		return errRollback
+	})
+	r.ErrorIs(err, errRollback)
+
+	spans := exporter.GetSpans()
+	r.Len(spans, 3)
+	attrs := func(s tracetest.SpanStub) map[attribute.Key]string {
+		m := map[attribute.Key]string{}
+		for _, kv := range s.Attributes {
+			m[kv.Key] = kv.Value.Emit()
+		}
+		return m
+	}
+
+	r.Equal("INSERT users", spans[0].Name)
+	insert := attrs(spans[0])
+	r.Equal(dbSystem(c.Dialect), insert["db.system"])
+	r.Equal("INSERT", insert["db.operation"])
+	r.Equal("users", insert["db.sql.table"])
+	r.Equal(txID, insert["pop.connection.id"])
+	r.True(strings.HasPrefix(insert["db.statement"], "INSERT INTO"), insert["db.statement"])
+
+	r.Equal("SELECT users", spans[1].Name)
+	r.Contains(attrs(spans[1])["db.statement"], "name != ?")
+	r.Equal(codes.Unset, spans[1].Status.Code)
+	// a missing record is not an error of the database.
+	r.Equal(codes.Unset, spans[2].Status.Code)
+
+	rm := metricdata.ResourceMetrics{}
+	r.NoError(reader.Collect(context.Background(), &rm))
+	found := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = m.Data
+		}
+	}
+	hist, ok := found["db.client.operation.duration"].(metricdata.Histogram[float64])
+	r.True(ok)
+	count := uint64(0)
+	for _, dp := range hist.DataPoints {
+		count += dp.Count
+	}
+	r.Equal(uint64(3), count)
+	r.Contains(found, "db.client.connections.open")
+	r.Contains(found, "db.client.connections.in_use")
+}
+
+// registrationCounter counts the callbacks registered by the meters of a
+// provider and not unregistered yet.
+type registrationCounter struct {
+	metric.MeterProvider
+	active int
+}
+
+func (p *registrationCounter) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	return countingMeter{Meter: p.MeterProvider.Meter(name, opts...), p: p}
+}
+
+type countingMeter struct {
+	metric.Meter
+	p *registrationCounter
+}
+
+func (m countingMeter) RegisterCallback(f metric.Callback, instruments ...metric.Observable) (metric.Registration, error) {
+	reg, err := m.Meter.RegisterCallback(f, instruments...)
+	if err != nil {
+		return nil, err
+	}
+	m.p.active++
+	return countingRegistration{Registration: reg, p: m.p}, nil
+}
+
+type countingRegistration struct {
+	metric.Registration
+	p *registrationCounter
+}
+
+func (r countingRegistration) Unregister() error {
+	r.p.active--
+	return r.Registration.Unregister()
+}
+
+func Test_Connection_UseTelemetry_Close(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	deets := *PDB.Dialect.Details()
+	c, err := NewConnection(&deets)
+	r.NoError(err)
+	r.NoError(c.Open())
+
+	mp := &registrationCounter{MeterProvider: sdkmetric.NewMeterProvider()}
+	r.NoError(c.UseTelemetry(TelemetryOptions{MeterProvider: mp}))
+	r.NoError(c.UseTelemetry(TelemetryOptions{MeterProvider: mp}))
+	r.Equal(1, mp.active)
+
+	r.NoError(c.Close())
+	r.Equal(0, mp.active)
+}
@@ -0,0 +1,196 @@
+package pop
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"sort"
+
+	"github.com/gobuffalo/pop/v6/columns"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// Tracked records the values of the columns of a model as they were read
+// from, or last written to, the database. Embed it in a model to have Update
+// and Save write only the columns which changed since, and skip the statement
+// when none did.
+//
+//	type User struct {
+//		pop.Tracked
+//		ID   int    `db:"id"`
+//		Name string `db:"name"`
+//	}
+type Tracked struct {
+	snapshot map[string]interface{} `db:"-"`
+}
+
+func (t *Tracked) tracked() *Tracked {
+	return t
+}
+
+// tracker is implemented by the models embedding Tracked.
+type tracker interface {
+	tracked() *Tracked
+}
+
+// Change is the change of the value of a column of a tracked model.
+type Change struct {
+	Column string
+	From   interface{}
+	To     interface{}
+}
+
+// Changes returns the changes of the columns of a tracked model since it was
+// loaded, created or updated, sorted by column. Values are compared as they
+// are sent to the database, so the From and To of a driver.Valuer are the
+// results of its Value method. It returns nil if the model doesn't embed
+// Tracked or wasn't loaded yet.
+//
+//	u.Name = "Mark"
+//	for _, ch := range pop.Changes(&u) {
+//		fmt.Println(ch.Column, ch.From, ch.To)
+//	}
+func Changes(model interface{}) []Change {
+	t, ok := model.(tracker)
+	if !ok || t.tracked().snapshot == nil {
+		return nil
+	}
+	m := &Model{Value: model}
+	return m.changes(m.Columns())
+}
+
+// trackerMapper maps column names to the fields of models. Fields without a
+// db tag are named after the field, as they are by the columns package.
+var trackerMapper = reflectx.NewMapperFunc("db", func(s string) string { return s })
+
+// columnValues returns the values of the given columns of the model, as they
+// are sent to the database.
+func (m *Model) columnValues(cols columns.Columns) map[string]interface{} {
+	v := reflect.Indirect(reflect.ValueOf(m.Value))
+	fields := trackerMapper.TypeMap(v.Type())
+
+	values := map[string]interface{}{}
+	for name := range cols.Cols {
+		fi := fields.GetByPath(name)
+		if fi == nil {
+			continue
+		}
+		values[name] = columnValue(reflectx.FieldByIndexesReadOnly(v, fi.Index))
+	}
+	return values
+}
+
+// columnValue returns a copy of the value of a field, as sent to the database.
+func columnValue(f reflect.Value) interface{} {
+	if !f.IsValid() {
+		return nil
+	}
+	value := f.Interface()
+	if vr, ok := value.(driver.Valuer); ok {
+		if f.Kind() == reflect.Ptr && f.IsNil() {
+			return nil
+		}
+		if dv, err := vr.Value(); err == nil {
+			value = dv
+		}
+	}
+	if b, ok := value.([]byte); ok && b != nil {
+		value = append([]byte{}, b...)
+	}
+	return value
+}
+
+// track records the current values of the given columns of a tracked model,
+// or of all its columns if none are given.
+func (m *Model) track(cols ...columns.Columns) {
+	t, ok := m.Value.(tracker)
+	if !ok {
+		return
+	}
+	tr := t.tracked()
+	if len(cols) == 0 || tr.snapshot == nil {
+		tr.snapshot = m.columnValues(m.Columns())
+		return
+	}
+	for _, c := range cols {
+		for name, value := range m.columnValues(c) {
+			tr.snapshot[name] = value
+		}
+	}
+}
+
+// trackAll records the current values of the columns of the model, or of
+// every element of a slice of models or of pointers to models.
+func (m *Model) trackAll() {
+	if !m.isSlice() {
+		m.track()
+		return
+	}
+	rv := reflect.Indirect(reflect.ValueOf(m.Value))
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		if elem.Kind() != reflect.Ptr {
+			elem = elem.Addr()
+		} else if elem.IsNil() {
+			continue
+		}
+		(&Model{Value: elem.Interface(), ctx: m.ctx}).track()
+	}
+}
+
+// changes returns the changes of the given columns of a tracked model. Columns
+// missing from its snapshot are reported as changed.
+func (m *Model) changes(cols columns.Columns) []Change {
+	t, ok := m.Value.(tracker)
+	if !ok {
+		return nil
+	}
+	snapshot := t.tracked().snapshot
+
+	changes := []Change{}
+	for name, value := range m.columnValues(cols) {
+		old, ok := snapshot[name]
+		if ok && reflect.DeepEqual(old, value) {
+			continue
+		}
+		changes = append(changes, Change{Column: name, From: old, To: value})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Column < changes[j].Column
+	})
+	return changes
+}
+
+// changedColumns restricts the columns of an update of a tracked model to the
+// writeable columns which changed, along with the updated_at and optimistic
+// locking columns. It returns false if the model isn't tracked, and true with
+// no columns if nothing changed.
+func (m *Model) changedColumns(cols columns.Columns) (columns.Columns, bool) {
+	t, ok := m.Value.(tracker)
+	if !ok || t.tracked().snapshot == nil {
+		return cols, false
+	}
+
+	version := m.versionColumn()
+	compared := columns.NewColumnsWithAlias(cols.TableName, cols.TableAlias, cols.IDField)
+	for name, c := range cols.Writeable().Cols {
+		if name != "updated_at" && name != version {
+			compared.Cols[name] = c
+		}
+	}
+
+	changes := m.changes(compared)
+	if len(changes) == 0 {
+		return columns.Columns{}, true
+	}
+
+	changed := map[string]bool{"updated_at": true, version: true}
+	for _, ch := range changes {
+		changed[ch.Column] = true
+	}
+	for name := range cols.Cols {
+		if !changed[name] {
+			cols.Remove(name)
+		}
+	}
+	return cols, true
+}
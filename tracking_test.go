@@ -0,0 +1,155 @@
+package pop
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6/columns"
+	"github.com/stretchr/testify/require"
+)
+
+type TrackedUser struct {
+	Tracked
+	ID        int           `db:"id"`
+	UserName  string        `db:"user_name"`
+	Email     string        `db:"email"`
+	Name      nulls.String  `db:"name"`
+	Alive     nulls.Bool    `db:"alive"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+	BirthDate nulls.Time    `db:"birth_date"`
+	Bio       nulls.String  `db:"bio"`
+	Price     nulls.Float64 `db:"price"`
+	FullName  nulls.String  `db:"full_name" select:"name as full_name"`
+}
+
+func (TrackedUser) TableName() string {
+	return "users"
+}
+
+func Test_Changes(t *testing.T) {
+	r := require.New(t)
+
+	u := &TrackedUser{ID: 1, UserName: "mark", Name: nulls.NewString("Mark")}
+	r.Nil(Changes(u))
+	r.Nil(Changes(&User{}))
+
+	m := &Model{Value: u}
+	m.track()
+	r.Empty(Changes(u))
+
+	u.Name = nulls.NewString("Mark Bates")
+	u.Email = "mark@example.com"
+	r.Equal([]Change{
+		{Column: "email", From: "", To: "mark@example.com"},
+		{Column: "name", From: "Mark", To: "Mark Bates"},
+	}, Changes(u))
+
+	cols := m.Columns()
+	cols.Remove("id", "created_at")
+	cols, tracked := m.changedColumns(cols)
+	r.True(tracked)
+	r.ElementsMatch([]string{"email", "name", "updated_at"}, columnNames(cols.Cols))
+
+	m.track()
+	cols, tracked = m.changedColumns(m.Columns())
+	r.True(tracked)
+	r.Empty(cols.Cols)
+}
+
+func Test_Tracked_Slices(t *testing.T) {
+	r := require.New(t)
+
+	users := []TrackedUser{{ID: 1, UserName: "mark"}}
+	(&Model{Value: &users}).trackAll()
+	r.NotNil(Changes(&users[0]))
+
+	pointers := []*TrackedUser{{ID: 1, UserName: "mark"}, nil}
+	(&Model{Value: &pointers}).trackAll()
+	r.NotNil(Changes(pointers[0]))
+	r.Empty(Changes(pointers[0]))
+}
+
+func Test_Tracked_Columns(t *testing.T) {
+	r := require.New(t)
+
+	cols := (&Model{Value: &TrackedUser{}}).Columns()
+	r.ElementsMatch([]string{"id", "user_name", "email", "name", "alive", "created_at", "updated_at", "birth_date", "bio", "price", "full_name"}, columnNames(cols.Cols))
+}
+
+func Test_Update_Tracked(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		var updates []string
+		tx.Use(func(stmt *Statement, next func(*Statement) error) error {
+			if strings.HasPrefix(stmt.SQL, "UPDATE") {
+				updates = append(updates, stmt.SQL)
+			}
+			return next(stmt)
+		})
+
+		r.NoError(tx.Create(&TrackedUser{UserName: "mark", Email: "mark@example.com", Name: nulls.NewString("Mark")}))
+
+		u := &TrackedUser{}
+		r.NoError(tx.Where("user_name = ?", "mark").First(u))
+		updatedAt := u.UpdatedAt
+
+		r.NoError(tx.Update(u))
+		r.Empty(updates)
+		r.Equal(updatedAt, u.UpdatedAt)
+
+		u.Name = nulls.NewString("Mark Bates")
+		r.NoError(tx.Update(u))
+		r.Len(updates, 1)
+		r.Contains(updates[0], "name")
+		r.NotContains(updates[0], "email")
+		r.Empty(Changes(u))
+
+		r.NoError(tx.Save(u))
+		r.Len(updates, 1)
+
+		r.NoError(tx.Find(u, u.ID))
+		r.Equal("Mark Bates", u.Name.String)
+		r.Equal("mark@example.com", u.Email)
+	})
+}
+
+func Test_Upsert_Tracked(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		u := &TrackedUser{UserName: "mark", Email: "mark@example.com", Name: nulls.NewString("Mark")}
+		r.NoError(tx.Create(u))
+		r.Empty(Changes(u))
+
+		u.Name = nulls.NewString("Mark Bates")
+		r.NoError(tx.Upsert(u, []string{"id"}))
+		r.Empty(Changes(u))
+
+		var users []*TrackedUser
+		r.NoError(tx.Where("id = ?", u.ID).All(&users))
+		r.Len(users, 1)
+		r.NotNil(Changes(users[0]))
+		r.Empty(Changes(users[0]))
+
+		users[0].Email = "bates@example.com"
+		r.Equal([]Change{{Column: "email", From: "mark@example.com", To: "bates@example.com"}}, Changes(users[0]))
+	})
+}
+
+func columnNames(cols map[string]*columns.Column) []string {
+	names := []string{}
+	for name := range cols {
+		names = append(names, name)
+	}
+	return names
+}
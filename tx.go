@@ -3,6 +3,7 @@ package pop
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"math/rand"
 	"time"
@@ -18,6 +19,7 @@ func init() {
 type Tx struct {
 	ID int
 	*sqlx.Tx
+	savepoints int
 }
 
 func newTX(ctx context.Context, db *dB, opts *sql.TxOptions) (*Tx, error) {
@@ -39,11 +41,21 @@ func (tx *Tx) TransactionContext(ctx context.Context) (*Tx, error) {
 }
 
 // TransactionContextOptions simply returns the current transaction,
-// this is defined so it implements the `Store` interface.
-func (tx *Tx) TransactionContextOptions(_ context.Context, _ *sql.TxOptions) (*Tx, error) {
+// this is defined so it implements the `Store` interface. Options can not be
+// applied to a running transaction, so an error is returned if any is set.
+func (tx *Tx) TransactionContextOptions(_ context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if opts != nil && *opts != (sql.TxOptions{}) {
+		return nil, errors.New("transaction options can not be applied to a running transaction")
+	}
 	return tx, nil
 }
 
+// nextSavepoint returns a new savepoint name, unique within the transaction.
+func (tx *Tx) nextSavepoint() string {
+	tx.savepoints++
+	return fmt.Sprintf("sp_%d", tx.savepoints)
+}
+
 // Transaction simply returns the current transaction,
 // this is defined so it implements the `Store` interface.
 func (tx *Tx) Transaction() (*Tx, error) {
@@ -0,0 +1,142 @@
+package pop
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Transaction_Nested(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		r.NoError(tx.Create(&Composer{Name: "outer"}))
+
+		err := tx.Transaction(func(tx *Connection) error {
+			r.NoError(tx.Create(&Composer{Name: "inner"}))
+			return tx.Transaction(func(tx *Connection) error {
+				r.NoError(tx.Create(&Composer{Name: "failed"}))
+				return fmt.Errorf("failed")
+			})
+		})
+		r.EqualError(err, "failed")
+
+		count, err := tx.Count(&Composer{})
+		r.NoError(err)
+		r.Equal(1, count)
+
+		r.NoError(tx.Transaction(func(tx *Connection) error {
+			r.NoError(tx.Create(&Composer{Name: "inner"}))
+			return tx.Transaction(func(tx *Connection) error {
+				return tx.Create(&Composer{Name: "innermost"})
+			})
+		}))
+
+		count, err = tx.Count(&Composer{})
+		r.NoError(err)
+		r.Equal(3, count)
+
+		r.Panics(func() {
+			_ = tx.Transaction(func(tx *Connection) error {
+				r.NoError(tx.Create(&Composer{Name: "panic"}))
+				panic("inner function panic")
+			})
+		})
+
+		count, err = tx.Count(&Composer{})
+		r.NoError(err)
+		r.Equal(3, count)
+	})
+}
+
+func Test_Tx_TransactionContextOptions(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		_, err := tx.TX.TransactionContextOptions(tx.Context(), nil)
+		r.NoError(err)
+
+		_, err = tx.TX.TransactionContextOptions(tx.Context(), &sql.TxOptions{ReadOnly: true})
+		r.Error(err)
+	})
+}
+
+var errRetry = errors.New("retry")
+
+// retryDialect reports errRetry as retryable.
+type retryDialect struct {
+	Dialect
+}
+
+func (retryDialect) IsRetryable(err error) bool {
+	return errors.Is(err, errRetry)
+}
+
+func (d retryDialect) SavepointSQL(op, name string) (string, error) {
+	return d.Dialect.(savepointer).SavepointSQL(op, name)
+}
+
+func Test_Connection_TransactionWithRetry(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	c := PDB.copy()
+	c.Dialect = retryDialect{PDB.Dialect}
+	defer func() {
+		r.NoError(PDB.RawQuery("DELETE FROM composers WHERE name = ?", "retry").Exec())
+	}()
+
+	attempts := 0
+	err := c.TransactionWithRetry(func(tx *Connection) error {
+		attempts++
+		r.NoError(tx.Create(&Composer{Name: "retry"}))
+		if attempts < 3 {
+			return fmt.Errorf("attempt %d: %w", attempts, errRetry)
+		}
+		return nil
+	}, RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+	r.NoError(err)
+	r.Equal(3, attempts)
+
+	count, err := PDB.Where("name = ?", "retry").Count(&Composer{})
+	r.NoError(err)
+	r.Equal(1, count)
+
+	attempts = 0
+	err = c.TransactionWithRetry(func(tx *Connection) error {
+		attempts++
+		return errRetry
+	}, RetryPolicy{MaxAttempts: 2})
+	r.ErrorIs(err, errRetry)
+	r.Equal(2, attempts)
+
+	attempts = 0
+	err = c.TransactionWithRetry(func(tx *Connection) error {
+		attempts++
+		return errors.New("failed")
+	}, RetryPolicy{})
+	r.EqualError(err, "failed")
+	r.Equal(1, attempts)
+
+	attempts = 0
+	err = c.Transaction(func(tx *Connection) error {
+		return tx.TransactionWithRetry(func(tx *Connection) error {
+			attempts++
+			return errRetry
+		}, RetryPolicy{})
+	})
+	r.ErrorIs(err, errRetry)
+	r.Equal(1, attempts)
+}